@@ -10,11 +10,53 @@ import (
 
 type Config struct {
 	BaseBranch string `toml:"base_branch"`
-	Mode       string `toml:"mode"` // "branch", "working", "staged"
+	Mode       string `toml:"mode"`    // "branch", "working", "staged", "commit"
+	Backend    string `toml:"backend"` // "exec" or "native"
+
+	// Range is the commit or commit range (e.g. "main..HEAD" or a single
+	// SHA) diffed when Mode is "commit".
+	Range string `toml:"range"`
+
+	SignCommits        bool   `toml:"sign_commits"`
+	DefaultAuthorName  string `toml:"default_author_name"`
+	DefaultAuthorEmail string `toml:"default_author_email"`
+	GPGKey             string `toml:"gpg_key"`
+
+	// RenameThreshold is the -M/-C similarity percentage (1-100) used to
+	// detect renames and copies in diffs. Defaults to 50.
+	RenameThreshold int `toml:"rename_threshold"`
+
+	// AuthMode selects how the server authenticates requests: "none",
+	// "token", "basic" (resolved via .netrc / git-credential), or
+	// "local-uid" (SO_PEERCRED on the accepting socket).
+	AuthMode string `toml:"auth_mode"`
+	// AuthToken is the shared secret expected in `Authorization: Bearer`
+	// when AuthMode is "token".
+	AuthToken string `toml:"auth_token"`
+	// SocketPath is the Unix domain socket path the server listens on
+	// when AuthMode is "local-uid" (SO_PEERCRED is only meaningful for a
+	// Unix socket, not a TCP connection). Ignored for other auth modes.
+	SocketPath string `toml:"socket_path"`
 }
 
 // ValidModes are the allowed diff modes
-var ValidModes = []string{"branch", "working", "staged"}
+var ValidModes = []string{"branch", "working", "staged", "commit"}
+
+// ValidBackends are the allowed git backends
+var ValidBackends = []string{"exec", "native"}
+
+// ValidAuthModes are the allowed server authentication modes
+var ValidAuthModes = []string{"none", "token", "basic", "local-uid"}
+
+// IsValidAuthMode checks if an auth mode string is valid
+func IsValidAuthMode(mode string) bool {
+	for _, m := range ValidAuthModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
 
 // IsValidMode checks if a mode string is valid
 func IsValidMode(mode string) bool {
@@ -26,6 +68,16 @@ func IsValidMode(mode string) bool {
 	return false
 }
 
+// IsValidBackend checks if a backend string is valid
+func IsValidBackend(backend string) bool {
+	for _, b := range ValidBackends {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
 func Load() (*Config, error) {
 	configPath, err := getConfigPath()
 	if err != nil {
@@ -33,8 +85,11 @@ func Load() (*Config, error) {
 	}
 
 	cfg := &Config{
-		BaseBranch: "main",
-		Mode:       "branch",
+		BaseBranch:      "main",
+		Mode:            "branch",
+		Backend:         "exec",
+		RenameThreshold: 50,
+		AuthMode:        "none",
 	}
 
 	if _, err := os.Stat(configPath); err == nil {
@@ -42,6 +97,7 @@ func Load() (*Config, error) {
 			// If decode fails, use defaults
 			cfg.BaseBranch = "main"
 			cfg.Mode = "branch"
+			cfg.Backend = "exec"
 		}
 	}
 
@@ -50,6 +106,16 @@ func Load() (*Config, error) {
 		cfg.Mode = "branch"
 	}
 
+	// Validate backend
+	if !IsValidBackend(cfg.Backend) {
+		cfg.Backend = "exec"
+	}
+
+	// Validate auth mode
+	if !IsValidAuthMode(cfg.AuthMode) {
+		cfg.AuthMode = "none"
+	}
+
 	return cfg, nil
 }
 
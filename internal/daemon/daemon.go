@@ -20,6 +20,13 @@ type Info struct {
 
 type Registry struct {
 	Daemons map[string]*Info `json:"daemons"`
+
+	// KnownRepos maps a repo's RepoID (see gateway.go) to its path, so the
+	// gateway can recover a path to spawn a daemon for after the repo's
+	// last daemon stopped and CleanupStaleDaemons removed it from Daemons.
+	// Entries are added by RegisterDaemon and never removed, since RepoID
+	// is a one-way hash with no other way back to the path.
+	KnownRepos map[string]string `json:"known_repos"`
 }
 
 type Manager struct {
@@ -47,7 +54,8 @@ func NewManager() (*Manager, error) {
 
 func (m *Manager) loadRegistry() (*Registry, error) {
 	registry := &Registry{
-		Daemons: make(map[string]*Info),
+		Daemons:    make(map[string]*Info),
+		KnownRepos: make(map[string]string),
 	}
 
 	if _, err := os.Stat(m.registryPath); err == nil {
@@ -58,7 +66,10 @@ func (m *Manager) loadRegistry() (*Registry, error) {
 
 		if err := json.Unmarshal(data, registry); err != nil {
 			// If unmarshal fails, return empty registry
-			return &Registry{Daemons: make(map[string]*Info)}, nil
+			return &Registry{Daemons: make(map[string]*Info), KnownRepos: make(map[string]string)}, nil
+		}
+		if registry.KnownRepos == nil {
+			registry.KnownRepos = make(map[string]string)
 		}
 	}
 
@@ -131,9 +142,23 @@ func (m *Manager) RegisterDaemon(info *Info) error {
 	}
 
 	registry.Daemons[info.RepoPath] = info
+	registry.KnownRepos[RepoID(info.RepoPath)] = info.RepoPath
 	return m.saveRegistry(registry)
 }
 
+// ResolveRepoPath looks up the repo path last registered under repoID, so
+// a gateway can spawn a daemon for a repo even after its previous daemon
+// stopped and was cleaned up from the live Daemons map.
+func (m *Manager) ResolveRepoPath(repoID string) (string, bool, error) {
+	registry, err := m.loadRegistry()
+	if err != nil {
+		return "", false, err
+	}
+
+	path, ok := registry.KnownRepos[repoID]
+	return path, ok, nil
+}
+
 func (m *Manager) UnregisterDaemon(repoPath string) error {
 	registry, err := m.loadRegistry()
 	if err != nil {
@@ -0,0 +1,259 @@
+package daemon
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// registryPollInterval is how often the /repos/events stream re-reads
+// daemon-registry.json to report the daemons listed there.
+const registryPollInterval = 2 * time.Second
+
+// daemonStartTimeout bounds how long the gateway waits for an on-demand
+// daemon it just spawned to register itself before giving up.
+const daemonStartTimeout = 10 * time.Second
+
+// Gateway is a single long-lived process bound to a stable port that fronts
+// the per-repo daemons tracked in the registry, so users with many
+// checkouts don't have to remember N random ports.
+type Gateway struct {
+	manager *Manager
+	port    int
+
+	mu      sync.Mutex
+	proxies map[string]*httputil.ReverseProxy
+}
+
+// NewGateway creates a Gateway bound to port, backed by manager's registry.
+func NewGateway(manager *Manager, port int) *Gateway {
+	return &Gateway{
+		manager: manager,
+		port:    port,
+		proxies: make(map[string]*httputil.ReverseProxy),
+	}
+}
+
+// RepoID derives a stable, URL-safe identifier for a repo path so it can
+// appear in a route like /r/{repoID}/api/diff.
+func RepoID(repoPath string) string {
+	sum := sha1.Sum([]byte(repoPath))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Start begins serving the gateway: GET /repos, /r/{repoID}/... reverse
+// proxied to the matching per-repo daemon (started on demand), and a
+// /repos/events aggregated SSE stream.
+func (g *Gateway) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos", g.reposHandler)
+	mux.HandleFunc("/repos/events", g.aggregatedEventsHandler)
+	mux.HandleFunc("/r/", g.proxyHandler)
+
+	addr := fmt.Sprintf("127.0.0.1:%d", g.port)
+	fmt.Printf("Starting gateway on http://%s\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+type repoEntry struct {
+	ID       string `json:"id"`
+	RepoPath string `json:"repo_path"`
+	Port     int    `json:"port"`
+}
+
+func (g *Gateway) reposHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.listRepos(w, r)
+	case http.MethodPost:
+		g.registerRepo(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) listRepos(w http.ResponseWriter, r *http.Request) {
+	if err := g.manager.CleanupStaleDaemons(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	daemons, err := g.manager.ListDaemons()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]repoEntry, 0, len(daemons))
+	for _, d := range daemons {
+		entries = append(entries, repoEntry{ID: RepoID(d.RepoPath), RepoPath: d.RepoPath, Port: d.Port})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// registerRepo accepts {"repo_path": "..."} and spawns a daemon for it if
+// one isn't already running, so a client that knows a repo path can bring
+// it online without shelling out to `guck daemon start` itself.
+func (g *Gateway) registerRepo(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RepoPath string `json:"repo_path"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RepoPath == "" {
+		http.Error(w, "repo_path is required", http.StatusBadRequest)
+		return
+	}
+
+	info, err := g.manager.GetDaemonForRepo(req.RepoPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if info == nil || !g.manager.IsDaemonRunning(info.PID) {
+		info, err = g.spawnDaemon(req.RepoPath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(repoEntry{ID: RepoID(info.RepoPath), RepoPath: info.RepoPath, Port: info.Port})
+}
+
+// proxyHandler reverse-proxies /r/{repoID}/... to the daemon for repoID,
+// starting it on demand if there's no live entry in the registry.
+func (g *Gateway) proxyHandler(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/r/")
+	parts := strings.SplitN(rest, "/", 2)
+	repoID := parts[0]
+	if repoID == "" {
+		http.Error(w, "missing repo id", http.StatusBadRequest)
+		return
+	}
+	subPath := "/"
+	if len(parts) == 2 {
+		subPath = "/" + parts[1]
+	}
+
+	info, err := g.ensureDaemonRunning(repoID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	proxy := g.proxyFor(info)
+	r.URL.Path = subPath
+	proxy.ServeHTTP(w, r)
+}
+
+func (g *Gateway) proxyFor(info *Info) *httputil.ReverseProxy {
+	key := fmt.Sprintf("%s:%d", info.RepoPath, info.Port)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if proxy, ok := g.proxies[key]; ok {
+		return proxy
+	}
+
+	target, _ := url.Parse(fmt.Sprintf("http://127.0.0.1:%d", info.Port))
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	g.proxies[key] = proxy
+	return proxy
+}
+
+// ensureDaemonRunning finds the daemon registered for repoID, cleaning up
+// stale entries first and spawning a fresh daemon on demand if none is
+// live, using the repo path last seen for repoID (see RegisterDaemon /
+// ResolveRepoPath) since RepoID itself can't be reversed back to a path.
+func (g *Gateway) ensureDaemonRunning(repoID string) (*Info, error) {
+	if err := g.manager.CleanupStaleDaemons(); err != nil {
+		return nil, err
+	}
+
+	daemons, err := g.manager.ListDaemons()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range daemons {
+		if RepoID(info.RepoPath) == repoID && g.manager.IsDaemonRunning(info.PID) {
+			return info, nil
+		}
+	}
+
+	repoPath, ok, err := g.manager.ResolveRepoPath(repoID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no daemon has ever been registered for repo id %q; "+
+			"start it once with `guck daemon start` first", repoID)
+	}
+
+	return g.spawnDaemon(repoPath)
+}
+
+// spawnDaemon starts a detached daemon process for repoPath and waits for
+// it to register itself.
+func (g *Gateway) spawnDaemon(repoPath string) (*Info, error) {
+	cmd := exec.Command(os.Args[0], "daemon", "start", "--repo", repoPath)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to spawn daemon for %s: %w", repoPath, err)
+	}
+
+	deadline := time.Now().Add(daemonStartTimeout)
+	for time.Now().Before(deadline) {
+		info, err := g.manager.GetDaemonForRepo(repoPath)
+		if err == nil && info != nil && g.manager.IsDaemonRunning(info.PID) {
+			return info, nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("daemon for %s did not register within %s", repoPath, daemonStartTimeout)
+}
+
+// aggregatedEventsHandler streams one SSE feed for all repos: every
+// registryPollInterval it re-reads the registry and emits a repo_alive
+// event, with the repo id as data, for each daemon listed there. Entries
+// are not checked for liveness first, and the daemons' own /api/events
+// streams are not forwarded.
+func (g *Gateway) aggregatedEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ticker := time.NewTicker(registryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			daemons, err := g.manager.ListDaemons()
+			if err != nil {
+				continue
+			}
+			for _, d := range daemons {
+				fmt.Fprintf(w, "event: repo_alive\ndata: %s\n\n", RepoID(d.RepoPath))
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package git
+
+// Backend selects the implementation used to talk to a repository.
+type Backend string
+
+const (
+	// BackendExec shells out to the system `git` binary for every
+	// operation. It is the default and works anywhere git is on PATH.
+	BackendExec Backend = "exec"
+	// BackendNative drives most repository reads in-process via go-git,
+	// avoiding a process fork per call; the operations listed on
+	// nativeRepo still run the `git` binary.
+	BackendNative Backend = "native"
+)
+
+// Repository is the full surface the rest of the codebase depends on to
+// read and mutate a git repository. *Repo (the exec backend) and
+// *nativeRepo (the go-git backend) both satisfy it, so callers can switch
+// backends through the one interface; nativeRepo documents where its
+// results differ from the exec backend's.
+type Repository interface {
+	CurrentBranch() (string, error)
+	CurrentCommit() (string, error)
+	RepoPath() (string, error)
+	GetRemoteURL() (string, error)
+	GetDefaultBranch() string
+
+	GetDiff(mode DiffMode, baseBranch string) ([]FileInfo, error)
+	GetDiffFiles(baseBranch string) ([]FileInfo, error)
+	GetWorkingTreeDiff() ([]FileInfo, error)
+	GetStagedDiff() ([]FileInfo, error)
+	GetDiffWithContents(mode DiffMode, baseBranch string) ([]FileInfo, error)
+
+	HasUncommittedChanges() bool
+	HasStagedChanges() bool
+
+	Stage(filePath string) error
+	Unstage(filePath string) error
+	Discard(filePath string) error
+	Commit(message string) error
+	CommitWithOptions(message string, opts CommitOptions) error
+
+	StageHunk(patch string) error
+	UnstageHunk(patch string) error
+	DiscardHunk(patch string) error
+	StageLines(patch string) error
+	DiscardLines(patch string) error
+
+	GetFileAtHEAD(filePath string) (string, error)
+	GetFileAtRef(ref, filePath string) (string, error)
+	GetFileFromIndex(filePath string) (string, error)
+	GetWorkingFile(filePath string) (string, error)
+}
+
+// compile-time assertions that both backends implement Repository
+var (
+	_ Repository = (*Repo)(nil)
+	_ Repository = (*nativeRepo)(nil)
+)
+
+// OpenBackend opens a repository at path using the requested backend.
+// An empty or unrecognized backend falls back to BackendExec.
+func OpenBackend(path string, backend Backend) (Repository, error) {
+	switch backend {
+	case BackendNative:
+		return openNative(path)
+	case BackendExec, "":
+		return Open(path)
+	default:
+		return Open(path)
+	}
+}
@@ -0,0 +1,209 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenBackend_Exec tests opening a repository through OpenBackend with
+// the exec backend.
+func TestOpenBackend_Exec(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := OpenBackend(dir, BackendExec)
+	if err != nil {
+		t.Fatalf("OpenBackend(exec) failed: %v", err)
+	}
+
+	branch, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+	if branch != "main" && branch != "master" {
+		t.Errorf("expected main or master, got %q", branch)
+	}
+}
+
+// TestOpenBackend_UnknownFallsBackToExec tests that an unrecognized backend
+// name falls back to the exec implementation instead of failing.
+func TestOpenBackend_UnknownFallsBackToExec(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := OpenBackend(dir, Backend("bogus"))
+	if err != nil {
+		t.Fatalf("OpenBackend(bogus) failed: %v", err)
+	}
+	if _, ok := repo.(*Repo); !ok {
+		t.Errorf("expected fallback to *Repo, got %T", repo)
+	}
+}
+
+// backends is the table of Backend implementations exercised by the tests
+// below. Both must satisfy Repository identically for callers to be able to
+// switch backends without behavior changes.
+var backends = []Backend{BackendExec, BackendNative}
+
+// TestBackends_DiscardOnlyTouchesTargetFile tests that Discard leaves other
+// uncommitted changes untouched, across both backends.
+func TestBackends_DiscardOnlyTouchesTargetFile(t *testing.T) {
+	for _, backend := range backends {
+		backend := backend
+		t.Run(string(backend), func(t *testing.T) {
+			dir, cleanup := testRepo(t)
+			defer cleanup()
+
+			readmePath := filepath.Join(dir, "README.md")
+			if err := os.WriteFile(readmePath, []byte("# Discard me\n"), 0644); err != nil {
+				t.Fatalf("failed to modify README: %v", err)
+			}
+			keepPath := filepath.Join(dir, "keep.txt")
+			if err := os.WriteFile(keepPath, []byte("keep me\n"), 0644); err != nil {
+				t.Fatalf("failed to write keep.txt: %v", err)
+			}
+
+			repo, err := OpenBackend(dir, backend)
+			if err != nil {
+				t.Fatalf("OpenBackend(%s) failed: %v", backend, err)
+			}
+
+			if err := repo.Discard("README.md"); err != nil {
+				t.Fatalf("Discard failed: %v", err)
+			}
+
+			content, err := os.ReadFile(readmePath)
+			if err != nil {
+				t.Fatalf("failed to read README: %v", err)
+			}
+			if string(content) != "# Test Repo\n" {
+				t.Errorf("expected README.md reverted to committed content, got %q", content)
+			}
+
+			if _, err := os.Stat(keepPath); err != nil {
+				t.Errorf("expected keep.txt to survive Discard(\"README.md\"), stat err: %v", err)
+			}
+		})
+	}
+}
+
+// TestBackends_GetWorkingTreeDiff tests that the working tree diff reports
+// a populated patch for a modified file, across both backends.
+func TestBackends_GetWorkingTreeDiff(t *testing.T) {
+	for _, backend := range backends {
+		backend := backend
+		t.Run(string(backend), func(t *testing.T) {
+			dir, cleanup := testRepo(t)
+			defer cleanup()
+
+			if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Modified\n"), 0644); err != nil {
+				t.Fatalf("failed to modify README: %v", err)
+			}
+
+			repo, err := OpenBackend(dir, backend)
+			if err != nil {
+				t.Fatalf("OpenBackend(%s) failed: %v", backend, err)
+			}
+
+			files, err := repo.GetWorkingTreeDiff()
+			if err != nil {
+				t.Fatalf("GetWorkingTreeDiff failed: %v", err)
+			}
+
+			var found bool
+			for _, f := range files {
+				if f.Path == "README.md" {
+					found = true
+					if f.Patch == "" {
+						t.Error("expected a non-empty patch for README.md")
+					}
+				}
+			}
+			if !found {
+				t.Fatal("expected README.md in working tree diff")
+			}
+		})
+	}
+}
+
+// TestBackends_GetDiffFiles tests that branch-mode diff is computed from the
+// merge base rather than the base branch's tip, across both backends.
+func TestBackends_GetDiffFiles(t *testing.T) {
+	for _, backend := range backends {
+		backend := backend
+		t.Run(string(backend), func(t *testing.T) {
+			dir, cleanup := testRepo(t)
+			defer cleanup()
+
+			repo, err := OpenBackend(dir, backend)
+			if err != nil {
+				t.Fatalf("OpenBackend(%s) failed: %v", backend, err)
+			}
+
+			base, err := repo.CurrentBranch()
+			if err != nil {
+				t.Fatalf("CurrentBranch failed: %v", err)
+			}
+
+			// Exercise both backends via their concrete exec helpers so the
+			// setup doesn't depend on which Repository is under test.
+			execRepo, err := Open(dir)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			if err := execRepo.NewCmd("checkout", "-b", "feature").Run(); err != nil {
+				t.Fatalf("checkout -b feature failed: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0644); err != nil {
+				t.Fatalf("failed to write feature.txt: %v", err)
+			}
+			if err := execRepo.Stage("feature.txt"); err != nil {
+				t.Fatalf("Stage failed: %v", err)
+			}
+			if err := execRepo.Commit("Add feature"); err != nil {
+				t.Fatalf("Commit failed: %v", err)
+			}
+
+			// Advance base past the merge-base so a naive "diff against
+			// base's tip" implementation would miss feature.txt or report
+			// base-only changes as part of the diff.
+			if err := execRepo.NewCmd("checkout", base).Run(); err != nil {
+				t.Fatalf("checkout %s failed: %v", base, err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "base-only.txt"), []byte("base\n"), 0644); err != nil {
+				t.Fatalf("failed to write base-only.txt: %v", err)
+			}
+			if err := execRepo.Stage("base-only.txt"); err != nil {
+				t.Fatalf("Stage failed: %v", err)
+			}
+			if err := execRepo.Commit("Add base-only change"); err != nil {
+				t.Fatalf("Commit failed: %v", err)
+			}
+			if err := execRepo.NewCmd("checkout", "feature").Run(); err != nil {
+				t.Fatalf("checkout feature failed: %v", err)
+			}
+
+			files, err := repo.GetDiffFiles(base)
+			if err != nil {
+				t.Fatalf("GetDiffFiles failed: %v", err)
+			}
+
+			var sawFeature, sawBaseOnly bool
+			for _, f := range files {
+				switch f.Path {
+				case "feature.txt":
+					sawFeature = true
+				case "base-only.txt":
+					sawBaseOnly = true
+				}
+			}
+			if !sawFeature {
+				t.Error("expected feature.txt in diff against merge base")
+			}
+			if sawBaseOnly {
+				t.Error("base-only.txt should not appear: GetDiffFiles should diff from the merge base, not base's tip")
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Branch describes a single local branch and its upstream tracking state.
+type Branch struct {
+	Name     string
+	Upstream string // empty if the branch has no upstream
+	Track    string // e.g. "[ahead 2, behind 1]", empty if up to date or no upstream
+	SHA      string
+	RelDate  string
+	Subject  string
+}
+
+// branchFieldDelimiter separates the fields of a `for-each-ref` record. It
+// uses a NUL byte, which cannot appear in any of the requested fields.
+const branchFieldDelimiter = "\x00"
+
+// branchFormat requests refname, upstream, upstream tracking status,
+// commit hash, relative commit date, and subject for each branch. The
+// fields are joined with git's %00 escape, which for-each-ref expands to
+// branchFieldDelimiter in its output (a raw NUL can't be passed in argv).
+const branchFormat = "%(refname:short)%00" +
+	"%(upstream:short)%00" +
+	"%(upstream:track)%00" +
+	"%(objectname)%00" +
+	"%(committerdate:relative)%00" +
+	"%(subject)"
+
+// Branches returns the repository's local branches.
+func (r *Repo) Branches() ([]Branch, error) {
+	out, err := r.NewCmd("for-each-ref", "refs/heads", "--format="+branchFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	return parseBranchList(out), nil
+}
+
+func parseBranchList(output string) []Branch {
+	var branches []Branch
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, branchFieldDelimiter)
+		if len(fields) < 6 {
+			continue
+		}
+		branches = append(branches, Branch{
+			Name:     fields[0],
+			Upstream: fields[1],
+			Track:    fields[2],
+			SHA:      fields[3],
+			RelDate:  fields[4],
+			Subject:  fields[5],
+		})
+	}
+	return branches
+}
+
+// Checkout switches the working tree to an existing branch.
+func (r *Repo) Checkout(name string) error {
+	if out, err := r.NewCmd("checkout", name).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to checkout %s: %w: %s", name, err, out)
+	}
+	return nil
+}
+
+// CheckoutNew creates a new branch at startPoint and switches to it.
+func (r *Repo) CheckoutNew(name, startPoint string) error {
+	args := []string{"checkout", "-b", name}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	if out, err := r.NewCmd(args...).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w: %s", name, err, out)
+	}
+	return nil
+}
+
+// DeleteBranch removes a local branch. If force is false, git refuses to
+// delete a branch that hasn't been fully merged.
+func (r *Repo) DeleteBranch(name string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if out, err := r.NewCmd("branch", flag, name).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w: %s", name, err, out)
+	}
+	return nil
+}
+
+// MergeConflictError indicates an operation stopped with unresolved merge
+// conflicts, so a caller can offer conflict-resolution UI instead of just
+// surfacing a generic error.
+type MergeConflictError struct {
+	Paths []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("conflicts in %d file(s): %s", len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// ErrFastForwardNotPossible is returned by Merge when opts.FastForwardOnly
+// is set and the merge would require creating a merge commit.
+var ErrFastForwardNotPossible = errors.New("fast-forward not possible")
+
+// MergeOptions configures a merge started with Merge.
+type MergeOptions struct {
+	// FastForwardOnly passes --ff-only: the merge fails with
+	// ErrFastForwardNotPossible instead of creating a merge commit.
+	FastForwardOnly bool
+	// NoFastForward passes --no-ff: always create a merge commit, even
+	// when a fast-forward is possible.
+	NoFastForward bool
+}
+
+// Merge merges ref into the current branch. On conflicts, it returns a
+// *MergeConflictError listing the conflicted paths so a caller can
+// distinguish that case from other failures.
+func (r *Repo) Merge(ref string, opts MergeOptions) error {
+	args := []string{"merge"}
+	switch {
+	case opts.FastForwardOnly:
+		args = append(args, "--ff-only")
+	case opts.NoFastForward:
+		args = append(args, "--no-ff")
+	}
+	args = append(args, ref)
+
+	out, err := r.NewCmd(args...).WithEnv("GIT_EDITOR=true").RunWithOutput()
+	if err == nil {
+		return nil
+	}
+
+	if paths, pErr := r.conflictedPaths(); pErr == nil && len(paths) > 0 {
+		return &MergeConflictError{Paths: paths}
+	}
+	if opts.FastForwardOnly && strings.Contains(out, "Not possible to fast-forward") {
+		return ErrFastForwardNotPossible
+	}
+	return fmt.Errorf("failed to merge %s: %w: %s", ref, err, out)
+}
+
+// AbortMerge cancels an in-progress conflicted merge and restores the
+// pre-merge HEAD.
+func (r *Repo) AbortMerge() error {
+	if out, err := r.NewCmd("merge", "--abort").RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to abort merge: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Rebase replays the current branch's commits onto onto. This is the
+// simple one-shot form; for a step-by-step interactive rebase with
+// per-commit actions, use StartRebase.
+func (r *Repo) Rebase(onto string) error {
+	out, err := r.NewCmd("rebase", onto).WithEnv("GIT_EDITOR=true").RunWithOutput()
+	if err == nil {
+		return nil
+	}
+	if paths, pErr := r.conflictedPaths(); pErr == nil && len(paths) > 0 {
+		return &MergeConflictError{Paths: paths}
+	}
+	return fmt.Errorf("failed to rebase onto %s: %w: %s", onto, err, out)
+}
+
+// AbortRebase cancels an in-progress rebase, restoring the pre-rebase HEAD.
+func (r *Repo) AbortRebase() error {
+	if out, err := r.NewCmd("rebase", "--abort").RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to abort rebase: %w: %s", err, out)
+	}
+	return nil
+}
+
+// ContinueRebase resumes an in-progress rebase after conflicts have been
+// resolved and staged.
+func (r *Repo) ContinueRebase() error {
+	out, err := r.NewCmd("rebase", "--continue").WithEnv("GIT_EDITOR=true").RunWithOutput()
+	if err == nil {
+		return nil
+	}
+	if paths, pErr := r.conflictedPaths(); pErr == nil && len(paths) > 0 {
+		return &MergeConflictError{Paths: paths}
+	}
+	return fmt.Errorf("failed to continue rebase: %w: %s", err, out)
+}
+
+// Remote describes a configured remote.
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// Remotes returns the repository's configured remotes.
+func (r *Repo) Remotes() ([]Remote, error) {
+	out, err := r.NewCmd("remote", "-v").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var remotes []Remote
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		name, url := fields[0], fields[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		remotes = append(remotes, Remote{Name: name, URL: url})
+	}
+	return remotes, nil
+}
+
+// AddRemote registers a new remote.
+func (r *Repo) AddRemote(name, url string) error {
+	if out, err := r.NewCmd("remote", "add", name, url).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to add remote %s: %w: %s", name, err, out)
+	}
+	return nil
+}
+
+// Fetch downloads objects and refs from remote without merging them.
+func (r *Repo) Fetch(remote string) error {
+	if out, err := r.NewCmd("fetch", remote).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w: %s", remote, err, out)
+	}
+	return nil
+}
+
+// Pull fetches and merges the current branch's upstream.
+func (r *Repo) Pull() error {
+	out, err := r.NewCmd("pull").WithEnv("GIT_EDITOR=true").RunWithOutput()
+	if err == nil {
+		return nil
+	}
+	if paths, pErr := r.conflictedPaths(); pErr == nil && len(paths) > 0 {
+		return &MergeConflictError{Paths: paths}
+	}
+	return fmt.Errorf("failed to pull: %w: %s", err, out)
+}
+
+// Push pushes the current branch to its upstream. If force is true, it
+// passes --force-with-lease, which rejects the push if the remote ref has
+// moved since the last fetch rather than blindly overwriting it.
+func (r *Repo) Push(force bool) error {
+	args := []string{"push"}
+	if force {
+		args = append(args, "--force-with-lease")
+	}
+	if out, err := r.NewCmd(args...).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to push: %w: %s", err, out)
+	}
+	return nil
+}
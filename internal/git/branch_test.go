@@ -0,0 +1,224 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestBranches tests listing local branches.
+func TestBranches(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if err := repo.CheckoutNew("feature", ""); err != nil {
+		t.Fatalf("CheckoutNew failed: %v", err)
+	}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+
+	var names []string
+	for _, b := range branches {
+		names = append(names, b.Name)
+	}
+	if !containsString(names, "feature") {
+		t.Errorf("expected branches to include feature, got %v", names)
+	}
+}
+
+// TestCheckoutAndDeleteBranch tests creating, switching to, and deleting a
+// branch.
+func TestCheckoutAndDeleteBranch(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+
+	if err := repo.CheckoutNew("feature", ""); err != nil {
+		t.Fatalf("CheckoutNew failed: %v", err)
+	}
+	branch, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+	if branch != "feature" {
+		t.Fatalf("expected feature branch, got %q", branch)
+	}
+
+	if err := repo.Checkout(base); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if err := repo.DeleteBranch("feature", false); err != nil {
+		t.Fatalf("DeleteBranch failed: %v", err)
+	}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	for _, b := range branches {
+		if b.Name == "feature" {
+			t.Error("expected feature branch to be deleted")
+		}
+	}
+}
+
+// TestMerge_FastForward tests a fast-forward merge.
+func TestMerge_FastForward(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+
+	if err := repo.CheckoutNew("feature", ""); err != nil {
+		t.Fatalf("CheckoutNew failed: %v", err)
+	}
+	newFile := filepath.Join(dir, "feature.txt")
+	if err := os.WriteFile(newFile, []byte("feature content\n"), 0644); err != nil {
+		t.Fatalf("failed to write feature file: %v", err)
+	}
+	if err := repo.Stage("feature.txt"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Add feature"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if err := repo.Checkout(base); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if err := repo.Merge("feature", MergeOptions{FastForwardOnly: true}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	if _, err := os.Stat(newFile); err != nil {
+		t.Errorf("expected feature.txt to exist after merge: %v", err)
+	}
+}
+
+// TestMerge_Conflict tests that Merge surfaces a *MergeConflictError when
+// the merge stops with unresolved conflicts.
+func TestMerge_Conflict(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+
+	if err := repo.CheckoutNew("feature", ""); err != nil {
+		t.Fatalf("CheckoutNew failed: %v", err)
+	}
+	if err := os.WriteFile(readme, []byte("# Feature change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Feature change"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if err := repo.Checkout(base); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if err := os.WriteFile(readme, []byte("# Base change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Base change"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	err = repo.Merge("feature", MergeOptions{})
+	if err == nil {
+		t.Fatal("expected a conflict error")
+	}
+	var conflictErr *MergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected *MergeConflictError, got %T: %v", err, err)
+	}
+	if len(conflictErr.Paths) != 1 || conflictErr.Paths[0] != "README.md" {
+		t.Errorf("expected conflict in README.md, got %v", conflictErr.Paths)
+	}
+
+	if err := repo.AbortMerge(); err != nil {
+		t.Fatalf("AbortMerge failed: %v", err)
+	}
+}
+
+// TestRemotes tests listing and adding remotes.
+func TestRemotes(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		t.Fatalf("Remotes failed: %v", err)
+	}
+	if len(remotes) != 0 {
+		t.Fatalf("expected 0 remotes initially, got %d", len(remotes))
+	}
+
+	if err := repo.AddRemote("origin", "https://example.com/repo.git"); err != nil {
+		t.Fatalf("AddRemote failed: %v", err)
+	}
+
+	remotes, err = repo.Remotes()
+	if err != nil {
+		t.Fatalf("Remotes failed: %v", err)
+	}
+	if len(remotes) != 1 {
+		t.Fatalf("expected 1 remote, got %d", len(remotes))
+	}
+	if remotes[0].Name != "origin" || remotes[0].URL != "https://example.com/repo.git" {
+		t.Errorf("unexpected remote: %+v", remotes[0])
+	}
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
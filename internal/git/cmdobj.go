@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+)
+
+// CmdRunner executes an *exec.Cmd. The default implementation just calls
+// through to the os/exec methods; tests can swap Repo.Runner for a fake
+// that returns canned output without a real git binary.
+type CmdRunner interface {
+	Run(cmd *exec.Cmd) error
+	Output(cmd *exec.Cmd) ([]byte, error)
+	CombinedOutput(cmd *exec.Cmd) ([]byte, error)
+}
+
+// execRunner is the default CmdRunner, backed by os/exec.
+type execRunner struct{}
+
+func (execRunner) Run(cmd *exec.Cmd) error                      { return cmd.Run() }
+func (execRunner) Output(cmd *exec.Cmd) ([]byte, error)         { return cmd.Output() }
+func (execRunner) CombinedOutput(cmd *exec.Cmd) ([]byte, error) { return cmd.CombinedOutput() }
+
+// CmdObj wraps a single `git` invocation built against a Repo, routing
+// Run, Output and RunWithOutput through the Repo's Runner so callers can
+// unit test the parsing logic around it without shelling out.
+type CmdObj struct {
+	cmd    *exec.Cmd
+	runner CmdRunner
+}
+
+// NewCmd builds a CmdObj for `git <args...>` rooted at r.path, executed via
+// r.Runner (or the real os/exec runner if unset).
+func (r *Repo) NewCmd(args ...string) *CmdObj {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.path
+	runner := r.Runner
+	if runner == nil {
+		runner = execRunner{}
+	}
+	return &CmdObj{cmd: cmd, runner: runner}
+}
+
+// Args returns the full argv of the underlying command, including "git".
+func (c *CmdObj) Args() []string {
+	return c.cmd.Args
+}
+
+// WithEnv appends extra "KEY=VALUE" entries onto the command's environment
+// (starting from os.Environ()), e.g. GIT_EDITOR=true to suppress an
+// interactive editor prompt. Returns c for chaining.
+func (c *CmdObj) WithEnv(extra ...string) *CmdObj {
+	c.cmd.Env = append(os.Environ(), extra...)
+	return c
+}
+
+// Run runs the command, discarding any output, and returns its error.
+func (c *CmdObj) Run() error {
+	return c.runner.Run(c.cmd)
+}
+
+// Output runs the command and returns its standard output as a string.
+func (c *CmdObj) Output() (string, error) {
+	out, err := c.runner.Output(c.cmd)
+	return string(out), err
+}
+
+// RunWithOutput runs the command and returns its combined stdout+stderr,
+// useful for error messages that should include git's diagnostic output.
+func (c *CmdObj) RunWithOutput() (string, error) {
+	out, err := c.runner.CombinedOutput(c.cmd)
+	return string(out), err
+}
+
+// RunAndStream starts the command and streams its stdout line-by-line on
+// the returned channel, which is closed once the command exits. Intended
+// for long-running commands (e.g. diffing a very large tree) where a
+// caller wants to render progress instead of blocking until completion.
+// It starts the command directly and does not go through the Runner.
+func (c *CmdObj) RunAndStream() (<-chan string, error) {
+	stdout, err := c.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	lines := make(chan string, 16)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		_ = c.cmd.Wait()
+	}()
+	return lines, nil
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+// fakeCmdRunner returns canned output keyed by the command's args (joined,
+// excluding the leading "git"), letting tests exercise parsing logic
+// (getMergeBase, GetDiffFiles, ...) without a real git binary.
+type fakeCmdRunner struct {
+	output map[string]string
+	err    map[string]error
+}
+
+func (f *fakeCmdRunner) key(cmd *exec.Cmd) string {
+	return fmt.Sprint(cmd.Args[1:])
+}
+
+func (f *fakeCmdRunner) Run(cmd *exec.Cmd) error {
+	return f.err[f.key(cmd)]
+}
+
+func (f *fakeCmdRunner) Output(cmd *exec.Cmd) ([]byte, error) {
+	return []byte(f.output[f.key(cmd)]), f.err[f.key(cmd)]
+}
+
+func (f *fakeCmdRunner) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	return f.Output(cmd)
+}
+
+// TestGetMergeBase_FakeRunner tests getMergeBase against canned merge-base
+// output without shelling out to git.
+func TestGetMergeBase_FakeRunner(t *testing.T) {
+	repo := &Repo{path: "/fake/repo", Runner: &fakeCmdRunner{
+		output: map[string]string{
+			"[merge-base origin/main HEAD]": "abc123\n",
+		},
+	}}
+
+	got := repo.getMergeBase("main")
+	if got != "abc123" {
+		t.Errorf("expected %q, got %q", "abc123", got)
+	}
+}
+
+// TestGetMergeBase_FakeRunner_FallsBackToLocalBranch tests that getMergeBase
+// falls back to the local branch name when the origin remote-tracking ref
+// doesn't resolve.
+func TestGetMergeBase_FakeRunner_FallsBackToLocalBranch(t *testing.T) {
+	repo := &Repo{path: "/fake/repo", Runner: &fakeCmdRunner{
+		output: map[string]string{
+			"[merge-base main HEAD]": "def456\n",
+		},
+		err: map[string]error{
+			"[merge-base origin/main HEAD]": fmt.Errorf("unknown revision"),
+		},
+	}}
+
+	got := repo.getMergeBase("main")
+	if got != "def456" {
+		t.Errorf("expected %q, got %q", "def456", got)
+	}
+}
+
+// TestGetDiffFiles_FakeRunner tests GetDiffFiles parsing canned diff output
+// without a real git binary.
+func TestGetDiffFiles_FakeRunner(t *testing.T) {
+	diff := "diff --git a/foo.go b/foo.go\n" +
+		"index abc123..def456 100644\n" +
+		"--- a/foo.go\n" +
+		"+++ b/foo.go\n" +
+		"@@ -1,1 +1,2 @@\n" +
+		" package main\n" +
+		"+func foo() {}\n"
+
+	repo := &Repo{path: "/fake/repo", renameThreshold: 50, Runner: &fakeCmdRunner{
+		output: map[string]string{
+			"[merge-base origin/main HEAD]":                "abc123\n",
+			"[diff abc123...HEAD --no-color -M50% -C50%]": diff,
+		},
+	}}
+
+	files, err := repo.GetDiffFiles("main")
+	if err != nil {
+		t.Fatalf("GetDiffFiles failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	if files[0].Path != "foo.go" {
+		t.Errorf("expected foo.go, got %q", files[0].Path)
+	}
+}
+
+// TestCmdObj_RunAndStream tests that RunAndStream yields output line by line.
+func TestCmdObj_RunAndStream(t *testing.T) {
+	repo := &Repo{path: "."}
+	c := repo.NewCmd("--version")
+	lines, err := c.RunAndStream()
+	if err != nil {
+		t.Fatalf("RunAndStream failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	for line := range lines {
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if out.Len() == 0 {
+		t.Error("expected at least one line of output")
+	}
+}
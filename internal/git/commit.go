@@ -0,0 +1,136 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommitOptions customizes a commit beyond a plain message: a specific
+// author/committer identity, GPG signing, amending the current HEAD, or
+// allowing an empty commit.
+type CommitOptions struct {
+	Author     *Signature
+	Committer  *Signature
+	SignGPG    bool
+	SigningKey string
+	Amend      bool
+	AllowEmpty bool
+}
+
+// Signature reads the repo's configured author identity from git config
+// (user.name/user.email), falling back to the GIT_AUTHOR_* environment
+// variables for any value config lacks, so callers can inspect or override
+// the identity before committing.
+func (r *Repo) Signature() (Signature, error) {
+	name := r.gitConfigValue("user.name")
+	if name == "" {
+		name = os.Getenv("GIT_AUTHOR_NAME")
+	}
+	email := r.gitConfigValue("user.email")
+	if email == "" {
+		email = os.Getenv("GIT_AUTHOR_EMAIL")
+	}
+	if name == "" || email == "" {
+		return Signature{}, fmt.Errorf("no author identity configured: set user.name/user.email or GIT_AUTHOR_NAME/GIT_AUTHOR_EMAIL")
+	}
+	return Signature{Name: name, Email: email}, nil
+}
+
+// SigningKey returns the configured GPG signing key, or "" if none is set.
+func (r *Repo) SigningKey() string {
+	return r.gitConfigValue("user.signingkey")
+}
+
+// GPGSignByDefault reports whether commit.gpgsign is enabled in git config.
+func (r *Repo) GPGSignByDefault() bool {
+	return r.gitConfigValue("commit.gpgsign") == "true"
+}
+
+func (r *Repo) gitConfigValue(key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = r.path
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// CommitWithOptions creates a commit with the staged changes, applying the
+// given author/committer identity, signing, amend, and allow-empty options.
+func (r *Repo) CommitWithOptions(message string, opts CommitOptions) error {
+	args := []string{"commit", "-m", message}
+
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if opts.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+
+	signingKey := opts.SigningKey
+	if signingKey == "" {
+		signingKey = r.SigningKey()
+	}
+	switch {
+	case opts.SignGPG && signingKey != "":
+		args = append(args, "-S"+signingKey)
+	case opts.SignGPG:
+		args = append(args, "-S")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.path
+	cmd.Env = os.Environ()
+	if opts.Author != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_AUTHOR_NAME="+opts.Author.Name,
+			"GIT_AUTHOR_EMAIL="+opts.Author.Email,
+		)
+	}
+	if opts.Committer != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_COMMITTER_NAME="+opts.Committer.Name,
+			"GIT_COMMITTER_EMAIL="+opts.Committer.Email,
+		)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to commit: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Amend rewrites HEAD with a new message and/or identity, keeping the
+// currently staged tree (or HEAD's tree if nothing is staged).
+func (r *Repo) Amend(message string, opts CommitOptions) error {
+	opts.Amend = true
+	return r.CommitWithOptions(message, opts)
+}
+
+// AmendStaged rewrites HEAD's message. If includeStaged is true, any
+// currently staged changes are folded into the amended commit (the
+// default `git commit --amend` behavior). If false, staged changes are
+// set aside around the amend so only the message changes.
+func (r *Repo) AmendStaged(message string, includeStaged bool) error {
+	if includeStaged || !r.HasStagedChanges() {
+		return r.CommitWithOptions(message, CommitOptions{Amend: true, AllowEmpty: true})
+	}
+
+	if out, err := r.NewCmd("stash", "push", "--staged", "-m", "cerebro: set aside for amend").RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to set aside staged changes before amend: %w: %s", err, out)
+	}
+
+	commitErr := r.CommitWithOptions(message, CommitOptions{Amend: true, AllowEmpty: true})
+
+	if out, err := r.NewCmd("stash", "pop", "--index").RunWithOutput(); err != nil {
+		if commitErr != nil {
+			return commitErr
+		}
+		return fmt.Errorf("amended commit, but failed to restore staged changes: %w: %s", err, out)
+	}
+	return commitErr
+}
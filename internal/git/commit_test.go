@@ -0,0 +1,98 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCommitWithOptions_CustomAuthor tests committing with an explicit
+// author/committer identity instead of relying on git config.
+func TestCommitWithOptions_CustomAuthor(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Custom author\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+
+	opts := CommitOptions{
+		Author:    &Signature{Name: "Custom Author", Email: "custom@example.com"},
+		Committer: &Signature{Name: "Custom Author", Email: "custom@example.com"},
+	}
+	if err := repo.CommitWithOptions("Custom author commit", opts); err != nil {
+		t.Fatalf("CommitWithOptions failed: %v", err)
+	}
+
+	if repo.HasStagedChanges() {
+		t.Error("expected no staged changes after CommitWithOptions()")
+	}
+}
+
+// TestAmend tests rewriting the message of the current HEAD commit.
+func TestAmend(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	initialCommit, _ := repo.CurrentCommit()
+
+	if err := repo.Amend("Amended message", CommitOptions{}); err != nil {
+		t.Fatalf("Amend failed: %v", err)
+	}
+
+	newCommit, _ := repo.CurrentCommit()
+	if newCommit == initialCommit {
+		t.Error("expected a new commit hash after Amend()")
+	}
+}
+
+// TestAmendStaged_ExcludeStaged tests that AmendStaged(message, false)
+// changes only the message, leaving staged changes staged rather than
+// folding them into the amended commit.
+func TestAmendStaged_ExcludeStaged(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Staged change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+
+	if err := repo.AmendStaged("Message only", false); err != nil {
+		t.Fatalf("AmendStaged failed: %v", err)
+	}
+
+	if !repo.HasStagedChanges() {
+		t.Error("expected staged changes to survive AmendStaged(message, false)")
+	}
+
+	content, err := repo.GetFileAtHEAD("README.md")
+	if err != nil {
+		t.Fatalf("GetFileAtHEAD failed: %v", err)
+	}
+	if content != "# Test Repo\n" {
+		t.Errorf("expected HEAD's tree to be unchanged, got %q", content)
+	}
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// OpenCtx is like Open, but the `git rev-parse` used to locate the
+// repository root is cancelled if ctx is done before it completes.
+func OpenCtx(ctx context.Context, path string) (*Repo, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git repository: %w", err)
+	}
+
+	repoPath := strings.TrimSpace(string(out))
+	return &Repo{path: repoPath, renameThreshold: 50}, nil
+}
+
+// CurrentBranchCtx is like CurrentBranch, but cancellable via ctx.
+func (r *Repo) CurrentBranchCtx(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = r.path
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CurrentCommitCtx is like CurrentCommit, but cancellable via ctx.
+func (r *Repo) CurrentCommitCtx(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = r.path
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current commit: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetDiffCtx is like GetDiff, but cancellable via ctx. DiffModeCommit is
+// served by GetCommitRangeDiff, which does not observe ctx.
+func (r *Repo) GetDiffCtx(ctx context.Context, mode DiffMode, baseBranch string) ([]FileInfo, error) {
+	switch mode {
+	case DiffModeWorking:
+		return r.GetWorkingTreeDiffCtx(ctx)
+	case DiffModeStaged:
+		return r.GetStagedDiffCtx(ctx)
+	case DiffModeCommit:
+		return r.GetCommitRangeDiff(baseBranch)
+	case DiffModeBranch:
+		fallthrough
+	default:
+		return r.GetDiffFilesCtx(ctx, baseBranch)
+	}
+}
+
+// GetDiffFilesCtx is like GetDiffFiles, but its diff is cancellable via
+// ctx; the merge-base lookup before it does not observe ctx.
+func (r *Repo) GetDiffFilesCtx(ctx context.Context, baseBranch string) ([]FileInfo, error) {
+	mergeBase := r.getMergeBase(baseBranch)
+	if mergeBase == "" {
+		return nil, fmt.Errorf("failed to find merge base with %s", baseBranch)
+	}
+
+	args := append([]string{"diff", mergeBase + "...HEAD", "--no-color"}, r.renameDetectionArgs()...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	return parseDiffOutput(string(out)), nil
+}
+
+// GetWorkingTreeDiffCtx is like GetWorkingTreeDiff, but its diff and
+// untracked-file listing are cancellable via ctx; the Status lookup merged
+// in afterwards does not observe ctx.
+func (r *Repo) GetWorkingTreeDiffCtx(ctx context.Context) ([]FileInfo, error) {
+	args := append([]string{"diff", "HEAD", "--no-color"}, r.renameDetectionArgs()...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.path
+	output, err := cmd.Output()
+	if err != nil {
+		cachedArgs := append([]string{"diff", "--cached", "--no-color"}, r.renameDetectionArgs()...)
+		cmd = exec.CommandContext(ctx, "git", cachedArgs...)
+		cmd.Dir = r.path
+		output, err = cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working tree diff: %w", err)
+		}
+	}
+
+	untrackedCmd := exec.CommandContext(ctx, "git", "ls-files", "--others", "--exclude-standard")
+	untrackedCmd.Dir = r.path
+	untrackedOutput, _ := untrackedCmd.Output()
+
+	files := appendUntrackedFiles(r.path, parseDiffOutput(string(output)), untrackedOutput)
+	if entries, err := r.Status(); err == nil {
+		mergeStatusInfo(files, entries)
+	}
+	return files, nil
+}
+
+// GetStagedDiffCtx is like GetStagedDiff, but its diff is cancellable via
+// ctx; the Status lookup merged in afterwards does not observe ctx.
+func (r *Repo) GetStagedDiffCtx(ctx context.Context) ([]FileInfo, error) {
+	args := append([]string{"diff", "--cached", "--no-color"}, r.renameDetectionArgs()...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged diff: %w", err)
+	}
+
+	files := parseDiffOutput(string(output))
+	if entries, err := r.Status(); err == nil {
+		mergeStatusInfo(files, entries)
+	}
+	return files, nil
+}
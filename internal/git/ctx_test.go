@@ -0,0 +1,95 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestOpenCtx_Cancelled tests that a cancelled context aborts Open.
+func TestOpenCtx_Cancelled(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := OpenCtx(ctx, dir)
+	if err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+}
+
+// TestCurrentBranchCtx tests that CurrentBranchCtx matches CurrentBranch.
+func TestCurrentBranchCtx(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	branch, err := repo.CurrentBranchCtx(ctx)
+	if err != nil {
+		t.Fatalf("CurrentBranchCtx failed: %v", err)
+	}
+	if branch != "main" && branch != "master" {
+		t.Errorf("expected main or master, got %q", branch)
+	}
+}
+
+// TestGetWorkingTreeDiffCtx_RenameOldPath tests that the context-aware diff
+// path (used by the server handlers) also gets the authoritative rename
+// info merged in, not just the non-ctx GetWorkingTreeDiff.
+func TestGetWorkingTreeDiffCtx_RenameOldPath(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	oldPath := filepath.Join(dir, "README.md")
+	newPath := filepath.Join(dir, "RENAMED.md")
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatalf("failed to rename file: %v", err)
+	}
+	// git status only reports a rename once both sides of the move are staged.
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Stage("RENAMED.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	files, err := repo.GetWorkingTreeDiffCtx(ctx)
+	if err != nil {
+		t.Fatalf("GetWorkingTreeDiffCtx failed: %v", err)
+	}
+
+	var found bool
+	for _, f := range files {
+		if f.Path == "RENAMED.md" {
+			found = true
+			if f.Status != "renamed" {
+				t.Errorf("expected status renamed, got %q", f.Status)
+			}
+			if f.OldPath != "README.md" {
+				t.Errorf("expected OldPath README.md, got %q", f.OldPath)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected RENAMED.md in working tree diff")
+	}
+}
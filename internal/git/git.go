@@ -18,20 +18,38 @@ const (
 	DiffModeWorking DiffMode = "working"
 	// DiffModeStaged shows only staged changes (what would be committed)
 	DiffModeStaged DiffMode = "staged"
+	// DiffModeCommit shows the changes introduced by a specific commit
+	// or commit range (see Config.Range)
+	DiffModeCommit DiffMode = "commit"
 )
 
 type Repo struct {
 	path string // Repository root path
+
+	// renameThreshold is the -M/-C similarity percentage used when
+	// detecting renames and copies in diffs. Defaults to 50.
+	renameThreshold int
+
+	// Runner executes the `git` commands built by NewCmd. Defaults to the
+	// real os/exec-backed runner; tests can swap it for a fake to exercise
+	// parsing logic (GetDiffFiles, GetWorkingTreeDiff, getMergeBase, ...)
+	// without a real git binary.
+	Runner CmdRunner
 }
 
 type FileInfo struct {
 	Path      string        `json:"path"`
+	OldPath   string        `json:"old_path,omitempty"` // set for renames and copies
 	Status    string        `json:"status"`
 	Additions int           `json:"additions"`
 	Deletions int           `json:"deletions"`
 	Patch     string        `json:"patch"`
 	OldFile   *FileContents `json:"old_file,omitempty"`
 	NewFile   *FileContents `json:"new_file,omitempty"`
+
+	// Submodule holds the raw porcelain=v2 submodule state field (see
+	// FileEntry.Submodule) when Status is "submodule".
+	Submodule string `json:"submodule,omitempty"`
 }
 
 type FileContents struct {
@@ -50,29 +68,41 @@ func Open(path string) (*Repo, error) {
 	}
 
 	repoPath := strings.TrimSpace(string(out))
-	return &Repo{path: repoPath}, nil
+	return &Repo{path: repoPath, renameThreshold: 50}, nil
+}
+
+// SetRenameThreshold sets the similarity percentage (1-100) used when
+// detecting renames and copies. Defaults to 50.
+func (r *Repo) SetRenameThreshold(pct int) {
+	r.renameThreshold = pct
+}
+
+// renameDetectionArgs returns the -M/-C flags passed to `git diff` to
+// enable rename and copy detection at the configured threshold.
+func (r *Repo) renameDetectionArgs() []string {
+	threshold := r.renameThreshold
+	if threshold <= 0 {
+		threshold = 50
+	}
+	return []string{fmt.Sprintf("-M%d%%", threshold), fmt.Sprintf("-C%d%%", threshold)}
 }
 
 // CurrentBranch returns the current branch name
 func (r *Repo) CurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = r.path
-	out, err := cmd.Output()
+	out, err := r.NewCmd("rev-parse", "--abbrev-ref", "HEAD").Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
-	return strings.TrimSpace(string(out)), nil
+	return strings.TrimSpace(out), nil
 }
 
 // CurrentCommit returns the current HEAD commit hash
 func (r *Repo) CurrentCommit() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
-	cmd.Dir = r.path
-	out, err := cmd.Output()
+	out, err := r.NewCmd("rev-parse", "HEAD").Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current commit: %w", err)
 	}
-	return strings.TrimSpace(string(out)), nil
+	return strings.TrimSpace(out), nil
 }
 
 // RepoPath returns the absolute path to the repository root
@@ -82,25 +112,21 @@ func (r *Repo) RepoPath() (string, error) {
 
 // GetRemoteURL returns the URL of the origin remote, or empty string if not found
 func (r *Repo) GetRemoteURL() (string, error) {
-	cmd := exec.Command("git", "remote", "get-url", "origin")
-	cmd.Dir = r.path
-	out, err := cmd.Output()
+	out, err := r.NewCmd("remote", "get-url", "origin").Output()
 	if err != nil {
 		// No origin remote
 		return "", nil
 	}
-	return strings.TrimSpace(string(out)), nil
+	return strings.TrimSpace(out), nil
 }
 
 // GetDefaultBranch attempts to determine the repository's default branch
 // by checking origin/HEAD, then falling back to common branch names
 func (r *Repo) GetDefaultBranch() string {
 	// Try to get default branch from origin/HEAD
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
-	cmd.Dir = r.path
-	out, err := cmd.Output()
+	out, err := r.NewCmd("symbolic-ref", "refs/remotes/origin/HEAD").Output()
 	if err == nil {
-		ref := strings.TrimSpace(string(out))
+		ref := strings.TrimSpace(out)
 		// Extract branch name from refs/remotes/origin/<branch>
 		if strings.HasPrefix(ref, "refs/remotes/origin/") {
 			return strings.TrimPrefix(ref, "refs/remotes/origin/")
@@ -111,15 +137,11 @@ func (r *Repo) GetDefaultBranch() string {
 	commonBranches := []string{"main", "master", "develop", "development"}
 	for _, branch := range commonBranches {
 		// Check remote branch first
-		cmd = exec.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+branch)
-		cmd.Dir = r.path
-		if cmd.Run() == nil {
+		if r.NewCmd("show-ref", "--verify", "--quiet", "refs/remotes/origin/"+branch).Run() == nil {
 			return branch
 		}
 		// Check local branch
-		cmd = exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
-		cmd.Dir = r.path
-		if cmd.Run() == nil {
+		if r.NewCmd("show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil {
 			return branch
 		}
 	}
@@ -137,97 +159,100 @@ func (r *Repo) GetDiffFiles(baseBranch string) ([]FileInfo, error) {
 	}
 
 	// Get diff from merge-base to HEAD
-	cmd := exec.Command("git", "diff", mergeBase+"...HEAD", "--no-color")
-	cmd.Dir = r.path
-	out, err := cmd.Output()
+	args := append([]string{"diff", mergeBase + "...HEAD", "--no-color"}, r.renameDetectionArgs()...)
+	out, err := r.NewCmd(args...).Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get diff: %w", err)
 	}
 
-	return parseDiffOutput(string(out)), nil
+	return parseDiffOutput(out), nil
 }
 
 // GetWorkingTreeDiff returns all uncommitted changes (staged + unstaged)
 func (r *Repo) GetWorkingTreeDiff() ([]FileInfo, error) {
 	// Get diff of working tree against HEAD
-	cmd := exec.Command("git", "diff", "HEAD", "--no-color")
-	cmd.Dir = r.path
-	output, err := cmd.Output()
+	args := append([]string{"diff", "HEAD", "--no-color"}, r.renameDetectionArgs()...)
+	output, err := r.NewCmd(args...).Output()
 	if err != nil {
 		// If HEAD doesn't exist (new repo), diff against empty tree
-		cmd = exec.Command("git", "diff", "--cached", "--no-color")
-		cmd.Dir = r.path
-		output, err = cmd.Output()
+		cachedArgs := append([]string{"diff", "--cached", "--no-color"}, r.renameDetectionArgs()...)
+		output, err = r.NewCmd(cachedArgs...).Output()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get working tree diff: %w", err)
 		}
 	}
 
 	// Get list of untracked files
-	untrackedCmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
-	untrackedCmd.Dir = r.path
-	untrackedOutput, _ := untrackedCmd.Output()
-
-	files := parseDiffOutput(string(output))
-
-	// Add untracked files
-	if len(untrackedOutput) > 0 {
-		untrackedFiles := strings.Split(strings.TrimSpace(string(untrackedOutput)), "\n")
-		for _, filePath := range untrackedFiles {
-			if filePath == "" {
-				continue
-			}
-			// Read file content for the patch
-			content, err := os.ReadFile(filepath.Join(r.path, filePath))
-			if err != nil {
-				continue
-			}
-			lines := strings.Split(string(content), "\n")
-			patch := fmt.Sprintf("diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", filePath, filePath, filePath, len(lines))
-			for _, line := range lines {
-				patch += "+" + line + "\n"
-			}
-			files = append(files, FileInfo{
-				Path:      filePath,
-				Status:    "untracked",
-				Additions: len(lines),
-				Deletions: 0,
-				Patch:     patch,
-			})
-		}
-	}
+	untrackedOutput, _ := r.NewCmd("ls-files", "--others", "--exclude-standard").Output()
 
+	files := appendUntrackedFiles(r.path, parseDiffOutput(output), []byte(untrackedOutput))
+	if entries, err := r.Status(); err == nil {
+		mergeStatusInfo(files, entries)
+	}
 	return files, nil
 }
 
+// appendUntrackedFiles synthesizes a FileInfo (with a fabricated "new file"
+// patch) for each untracked file path in untrackedOutput and appends it to
+// files.
+func appendUntrackedFiles(repoPath string, files []FileInfo, untrackedOutput []byte) []FileInfo {
+	if len(untrackedOutput) == 0 {
+		return files
+	}
+
+	untrackedFiles := strings.Split(strings.TrimSpace(string(untrackedOutput)), "\n")
+	for _, filePath := range untrackedFiles {
+		if filePath == "" {
+			continue
+		}
+		// Read file content for the patch
+		content, err := os.ReadFile(filepath.Join(repoPath, filePath))
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		patch := fmt.Sprintf("diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", filePath, filePath, filePath, len(lines))
+		for _, line := range lines {
+			patch += "+" + line + "\n"
+		}
+		files = append(files, FileInfo{
+			Path:      filePath,
+			Status:    "untracked",
+			Additions: len(lines),
+			Deletions: 0,
+			Patch:     patch,
+		})
+	}
+	return files
+}
+
 // GetStagedDiff returns only staged changes (what would be committed)
 func (r *Repo) GetStagedDiff() ([]FileInfo, error) {
-	cmd := exec.Command("git", "diff", "--cached", "--no-color")
-	cmd.Dir = r.path
-	output, err := cmd.Output()
+	args := append([]string{"diff", "--cached", "--no-color"}, r.renameDetectionArgs()...)
+	output, err := r.NewCmd(args...).Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get staged diff: %w", err)
 	}
 
-	return parseDiffOutput(string(output)), nil
+	files := parseDiffOutput(output)
+	if entries, err := r.Status(); err == nil {
+		mergeStatusInfo(files, entries)
+	}
+	return files, nil
 }
 
 // HasUncommittedChanges checks if there are any uncommitted changes
 func (r *Repo) HasUncommittedChanges() bool {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = r.path
-	output, err := cmd.Output()
+	output, err := r.NewCmd("status", "--porcelain").Output()
 	if err != nil {
 		return false
 	}
-	return len(strings.TrimSpace(string(output))) > 0
+	return len(strings.TrimSpace(output)) > 0
 }
 
 // HasStagedChanges checks if there are any staged changes
 func (r *Repo) HasStagedChanges() bool {
-	cmd := exec.Command("git", "diff", "--cached", "--quiet")
-	cmd.Dir = r.path
-	err := cmd.Run()
+	err := r.NewCmd("diff", "--cached", "--quiet").Run()
 	// Exit code 1 means there are changes
 	return err != nil
 }
@@ -259,13 +284,19 @@ func parseDiffOutput(diffOutput string) []FileInfo {
 		// Determine status
 		status := "modified"
 		fullPatch := "diff --git " + part
+		oldPath := ""
 
-		if strings.Contains(part, "new file mode") {
+		switch {
+		case strings.Contains(part, "new file mode"):
 			status = "added"
-		} else if strings.Contains(part, "deleted file mode") {
+		case strings.Contains(part, "deleted file mode"):
 			status = "deleted"
-		} else if strings.Contains(part, "rename from") {
+		case strings.Contains(part, "rename from"):
 			status = "renamed"
+			oldPath = extractHeaderLineValue(lines, "rename from ")
+		case strings.Contains(part, "copy from"):
+			status = "copied"
+			oldPath = extractHeaderLineValue(lines, "copy from ")
 		}
 
 		// Count additions and deletions
@@ -281,6 +312,7 @@ func parseDiffOutput(diffOutput string) []FileInfo {
 
 		files = append(files, FileInfo{
 			Path:      filePath,
+			OldPath:   oldPath,
 			Status:    status,
 			Additions: additions,
 			Deletions: deletions,
@@ -291,13 +323,28 @@ func parseDiffOutput(diffOutput string) []FileInfo {
 	return files
 }
 
-// GetDiff returns files based on the specified mode
+// extractHeaderLineValue returns the text following prefix on the first
+// diff header line that starts with it (e.g. "rename from " -> "old.go").
+func extractHeaderLineValue(lines []string, prefix string) string {
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix)
+		}
+	}
+	return ""
+}
+
+// GetDiff returns files based on the specified mode. For DiffModeCommit,
+// baseBranch is interpreted as the commit or range (e.g. "main..HEAD" or a
+// single SHA) to diff, per Config.Range.
 func (r *Repo) GetDiff(mode DiffMode, baseBranch string) ([]FileInfo, error) {
 	switch mode {
 	case DiffModeWorking:
 		return r.GetWorkingTreeDiff()
 	case DiffModeStaged:
 		return r.GetStagedDiff()
+	case DiffModeCommit:
+		return r.GetCommitRangeDiff(baseBranch)
 	case DiffModeBranch:
 		fallthrough
 	default:
@@ -305,73 +352,60 @@ func (r *Repo) GetDiff(mode DiffMode, baseBranch string) ([]FileInfo, error) {
 	}
 }
 
+// GetCommitRangeDiff returns the files changed by a single commit (a bare
+// SHA) or by a range like "main..HEAD".
+func (r *Repo) GetCommitRangeDiff(rangeSpec string) ([]FileInfo, error) {
+	if strings.Contains(rangeSpec, "..") {
+		out, err := r.NewCmd("diff", rangeSpec, "--no-color").Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diff for range %s: %w", rangeSpec, err)
+		}
+		return parseDiffOutput(out), nil
+	}
+	return r.CommitDiff(rangeSpec)
+}
+
 // Stage adds a file to the staging area
 func (r *Repo) Stage(filePath string) error {
-	cmd := exec.Command("git", "add", filePath)
-	cmd.Dir = r.path
-	return cmd.Run()
+	return r.NewCmd("add", filePath).Run()
 }
 
 // Unstage removes a file from the staging area
 func (r *Repo) Unstage(filePath string) error {
-	cmd := exec.Command("git", "reset", "HEAD", filePath)
-	cmd.Dir = r.path
-	return cmd.Run()
+	return r.NewCmd("reset", "HEAD", filePath).Run()
 }
 
 // Discard reverts a file to its last committed state, or deletes untracked files
 func (r *Repo) Discard(filePath string) error {
 	// First try git checkout (for tracked files)
-	cmd := exec.Command("git", "checkout", "--", filePath)
-	cmd.Dir = r.path
-	err := cmd.Run()
+	err := r.NewCmd("checkout", "--", filePath).Run()
 	if err != nil {
 		// If checkout fails, try git clean for untracked files
-		cmd = exec.Command("git", "clean", "-f", filePath)
-		cmd.Dir = r.path
-		return cmd.Run()
+		return r.NewCmd("clean", "-f", filePath).Run()
 	}
 	return nil
 }
 
-// Commit creates a new commit with the staged changes
+// Commit creates a new commit with the staged changes using the repo's
+// configured author/committer and signing settings. For custom author,
+// signing, or amend behavior, use CommitWithOptions.
 func (r *Repo) Commit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	cmd.Dir = r.path
-	return cmd.Run()
+	return r.CommitWithOptions(message, CommitOptions{})
 }
 
 // GetFileAtHEAD returns file contents at HEAD commit
 func (r *Repo) GetFileAtHEAD(filePath string) (string, error) {
-	cmd := exec.Command("git", "show", "HEAD:"+filePath)
-	cmd.Dir = r.path
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return string(output), nil
+	return r.NewCmd("show", "HEAD:"+filePath).Output()
 }
 
 // GetFileAtRef returns file contents at a specific ref (branch, commit, etc)
 func (r *Repo) GetFileAtRef(ref, filePath string) (string, error) {
-	cmd := exec.Command("git", "show", ref+":"+filePath)
-	cmd.Dir = r.path
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return string(output), nil
+	return r.NewCmd("show", ref+":"+filePath).Output()
 }
 
 // GetFileFromIndex returns file contents from the staging area
 func (r *Repo) GetFileFromIndex(filePath string) (string, error) {
-	cmd := exec.Command("git", "show", ":"+filePath)
-	cmd.Dir = r.path
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return string(output), nil
+	return r.NewCmd("show", ":"+filePath).Output()
 }
 
 // GetWorkingFile returns file contents from the working directory
@@ -451,11 +485,9 @@ func (r *Repo) GetDiffWithContents(mode DiffMode, baseBranch string) ([]FileInfo
 func (r *Repo) getMergeBase(baseBranch string) string {
 	// Try origin/baseBranch first, then baseBranch
 	for _, ref := range []string{"origin/" + baseBranch, baseBranch} {
-		cmd := exec.Command("git", "merge-base", ref, "HEAD")
-		cmd.Dir = r.path
-		output, err := cmd.Output()
+		output, err := r.NewCmd("merge-base", ref, "HEAD").Output()
 		if err == nil {
-			return strings.TrimSpace(string(output))
+			return strings.TrimSpace(output)
 		}
 	}
 	return ""
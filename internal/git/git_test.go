@@ -688,3 +688,34 @@ func TestParseDiffOutput_Empty(t *testing.T) {
 		t.Errorf("expected 0 files for empty diff, got %d", len(files))
 	}
 }
+
+// TestParseDiffOutput_Renamed tests parsing a rename diff
+func TestParseDiffOutput_Renamed(t *testing.T) {
+	diffOutput := `diff --git a/old.go b/new.go
+similarity index 95%
+rename from old.go
+rename to new.go
+index abc123..def456 100644
+--- a/old.go
++++ b/new.go
+@@ -1,3 +1,3 @@
+ package main
+-func old() {}
++func new() {}
+`
+
+	files := parseDiffOutput(diffOutput)
+
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	if files[0].Status != "renamed" {
+		t.Errorf("expected renamed, got %q", files[0].Status)
+	}
+	if files[0].OldPath != "old.go" {
+		t.Errorf("expected OldPath old.go, got %q", files[0].OldPath)
+	}
+	if files[0].Path != "new.go" {
+		t.Errorf("expected Path new.go, got %q", files[0].Path)
+	}
+}
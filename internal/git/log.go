@@ -0,0 +1,298 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Commit is a single entry in the commit history.
+type Commit struct {
+	SHA      string
+	ShortSHA string
+	Author   string
+	Date     string
+	Subject  string
+	Body     string
+	Parents  []string
+	Refs     []string // branch/tag names pointing at this commit, e.g. "HEAD -> main"
+
+	// GraphColumn and GraphRow locate this commit in the ASCII commit
+	// graph, computed by computeGraph over a topologically-ordered slice.
+	GraphColumn int
+	GraphRow    int
+
+	// ReflogDate and ReflogSubject are set when LogOptions.IncludeReflog
+	// is true and this commit has a matching `git reflog` entry, e.g. for
+	// annotating a branch/history UI with "2 hours ago: checkout: moving
+	// from main to feature".
+	ReflogDate    string
+	ReflogSubject string
+}
+
+// LogOptions filters and bounds a commit range query.
+type LogOptions struct {
+	Limit  int
+	Skip   int
+	Author string
+	Path   string
+	Grep   string
+
+	// IncludeReflog annotates each returned commit with its most recent
+	// matching `git reflog` entry (see Commit.ReflogDate/ReflogSubject).
+	IncludeReflog bool
+	// ReflogLimit bounds how many reflog entries are read when
+	// IncludeReflog is set. Defaults to 100 if zero.
+	ReflogLimit int
+}
+
+// logDelimiter separates the structured fields of a `git log` record, and
+// logRecordSeparator separates one commit's record from the next. Both are
+// non-printing bytes unlikely to appear in commit metadata.
+const logDelimiter = "\x1f"
+const logRecordSeparator = "\x1e"
+
+// logFormat produces one record per commit: sha, short sha, author, date,
+// subject, parents, body, ref names - each separated by logDelimiter -
+// terminated by logRecordSeparator.
+const logFormat = "%H" + logDelimiter + "%h" + logDelimiter + "%an <%ae>" + logDelimiter + "%ad" + logDelimiter + "%s" + logDelimiter + "%P" + logDelimiter + "%b" + logDelimiter + "%D" + logRecordSeparator
+
+// defaultReflogLimit is the number of reflog entries read when
+// LogOptions.IncludeReflog is set but ReflogLimit is unset.
+const defaultReflogLimit = 100
+
+// CountCommits returns the number of commits reachable from to but not
+// from from, i.e. `git rev-list --count from..to`.
+func (r *Repo) CountCommits(from, to string) (int, error) {
+	out, err := r.NewCmd("rev-list", "--count", from+".."+to).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits between %s and %s: %w", from, to, err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return count, nil
+}
+
+// LogRange returns the commits reachable from "to" but not from "from",
+// i.e. the commits introduced by from..to, newest first. Limit, Skip,
+// Author, Grep and Path apply as in Commits; IncludeReflog is not applied
+// and no graph position is computed.
+func (r *Repo) LogRange(from, to string, opts LogOptions) ([]Commit, error) {
+	args := []string{"log", "--date=iso-strict", "--pretty=format:" + logFormat}
+	if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", opts.Limit))
+	}
+	if opts.Skip > 0 {
+		args = append(args, fmt.Sprintf("--skip=%d", opts.Skip))
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Grep != "" {
+		args = append(args, "--grep="+opts.Grep)
+	}
+	args = append(args, from+".."+to)
+	if opts.Path != "" {
+		args = append(args, "--", opts.Path)
+	}
+
+	out, err := r.NewCmd(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log range %s..%s: %w", from, to, err)
+	}
+
+	return parseLogOutput(out), nil
+}
+
+func parseLogOutput(output string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(output, logRecordSeparator) {
+		record = strings.Trim(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, logDelimiter)
+		if len(fields) < 7 {
+			continue
+		}
+
+		var parents []string
+		if fields[5] != "" {
+			parents = strings.Fields(fields[5])
+		}
+
+		var refs []string
+		if len(fields) > 7 && fields[7] != "" {
+			for _, ref := range strings.Split(fields[7], ", ") {
+				refs = append(refs, strings.TrimSpace(ref))
+			}
+		}
+
+		commits = append(commits, Commit{
+			SHA:      fields[0],
+			ShortSHA: fields[1],
+			Author:   fields[2],
+			Date:     fields[3],
+			Subject:  fields[4],
+			Parents:  parents,
+			Body:     strings.TrimSpace(fields[6]),
+			Refs:     refs,
+		})
+	}
+	return commits
+}
+
+// computeGraph assigns a GraphColumn/GraphRow to each commit in a
+// newest-first, topologically-ordered slice (as produced by `git log
+// --topo-order`), using the same column-tracking approach as lazygit's
+// commit list loader: each column tracks the SHA it expects to see next: a
+// commit claims the column already waiting for its SHA (or opens a new one
+// if none is), then hands its column to its first parent and opens a new
+// column for each additional parent (merge commits).
+func computeGraph(commits []Commit) {
+	var columns []string // columns[i] is the SHA column i expects next
+
+	for row := range commits {
+		c := &commits[row]
+		c.GraphRow = row
+
+		col := -1
+		for i, expected := range columns {
+			if expected == c.SHA {
+				col = i
+				break
+			}
+		}
+		if col == -1 {
+			col = len(columns)
+			columns = append(columns, "")
+		}
+		c.GraphColumn = col
+
+		if len(c.Parents) == 0 {
+			columns[col] = ""
+			continue
+		}
+
+		columns[col] = c.Parents[0]
+		for _, parent := range c.Parents[1:] {
+			already := false
+			for _, expected := range columns {
+				if expected == parent {
+					already = true
+					break
+				}
+			}
+			if !already {
+				columns = append(columns, parent)
+			}
+		}
+	}
+}
+
+// Commits returns the repository's commit history (by default across all
+// refs, newest first), with each commit's ASCII-graph position computed
+// via computeGraph.
+func (r *Repo) Commits(opts LogOptions) ([]Commit, error) {
+	args := []string{"log", "--all", "--parents", "--topo-order", "--date=iso-strict", "--pretty=format:" + logFormat}
+	if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", opts.Limit))
+	}
+	if opts.Skip > 0 {
+		args = append(args, fmt.Sprintf("--skip=%d", opts.Skip))
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Grep != "" {
+		args = append(args, "--grep="+opts.Grep)
+	}
+	if opts.Path != "" {
+		args = append(args, "--", opts.Path)
+	}
+
+	out, err := r.NewCmd(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+
+	commits := parseLogOutput(out)
+	computeGraph(commits)
+
+	if opts.IncludeReflog {
+		limit := opts.ReflogLimit
+		if limit <= 0 {
+			limit = defaultReflogLimit
+		}
+		entries, err := r.reflogEntries(limit)
+		if err == nil {
+			for i := range commits {
+				if entry, ok := entries[commits[i].SHA]; ok {
+					commits[i].ReflogDate = entry.date
+					commits[i].ReflogSubject = entry.subject
+				}
+			}
+		}
+	}
+
+	return commits, nil
+}
+
+// reflogEntry is a single parsed `git reflog` record.
+type reflogEntry struct {
+	date    string
+	subject string
+}
+
+// reflogEntries reads the most recent n reflog entries and indexes them by
+// commit SHA, keeping the most recent entry when a SHA appears more than
+// once.
+func (r *Repo) reflogEntries(n int) (map[string]reflogEntry, error) {
+	out, err := r.NewCmd("reflog", fmt.Sprintf("-n%d", n), "--pretty=%H|%cr|%gs").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reflog: %w", err)
+	}
+
+	entries := make(map[string]reflogEntry)
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		sha := parts[0]
+		if _, exists := entries[sha]; exists {
+			continue
+		}
+		entries[sha] = reflogEntry{date: parts[1], subject: parts[2]}
+	}
+	return entries, nil
+}
+
+// emptyTreeSHA is git's well-known hash for an empty tree object, present
+// in every repository without needing to be created. Diffing a root
+// commit (one with no parent) against it yields that commit's full
+// contents, mirroring what `sha^!` produces for any non-root commit.
+const emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// CommitDiff returns the file changes introduced by a single commit,
+// i.e. the diff against its first parent. For a root commit (one with no
+// parent), it diffs against the empty tree instead, since sha^! silently
+// produces no output there.
+func (r *Repo) CommitDiff(sha string) ([]FileInfo, error) {
+	base := sha + "^"
+	if _, err := r.NewCmd("rev-parse", "--verify", "--quiet", base).Output(); err != nil {
+		base = emptyTreeSHA
+	}
+
+	out, err := r.NewCmd("diff", base, sha, "--no-color").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff for commit %s: %w", sha, err)
+	}
+	return parseDiffOutput(out), nil
+}
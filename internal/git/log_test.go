@@ -0,0 +1,235 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCommits tests fetching the commit log with graph metadata.
+func TestCommits(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	// Add a second commit so there's a parent/child relationship to graph.
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Second commit\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Second commit"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	commits, err := repo.Commits(LogOptions{})
+	if err != nil {
+		t.Fatalf("Commits failed: %v", err)
+	}
+
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+	if commits[0].Subject != "Second commit" {
+		t.Errorf("expected newest commit first, got %q", commits[0].Subject)
+	}
+	if commits[0].GraphColumn != 0 || commits[1].GraphColumn != 0 {
+		t.Errorf("expected a linear history to stay in column 0, got %d and %d", commits[0].GraphColumn, commits[1].GraphColumn)
+	}
+	if commits[1].Subject != "Initial commit" {
+		t.Errorf("expected second entry to be the initial commit, got %q", commits[1].Subject)
+	}
+}
+
+// TestCommits_Limit tests that Limit bounds the number of returned commits.
+func TestCommits_Limit(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Second commit\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Second commit"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	commits, err := repo.Commits(LogOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("Commits failed: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(commits))
+	}
+}
+
+// TestCommitDiff_RootCommit tests that CommitDiff falls back to diffing
+// against the empty tree for a commit with no parent, instead of silently
+// returning no changes.
+func TestCommitDiff_RootCommit(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	root, _ := repo.CurrentCommit()
+
+	files, err := repo.CommitDiff(root)
+	if err != nil {
+		t.Fatalf("CommitDiff failed: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("expected the root commit's diff to include its initial files, got none")
+	}
+}
+
+// TestCommitDiff_WithParent tests CommitDiff for an ordinary commit with a
+// single parent.
+func TestCommitDiff_WithParent(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Second commit\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Second commit"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	second, _ := repo.CurrentCommit()
+
+	files, err := repo.CommitDiff(second)
+	if err != nil {
+		t.Fatalf("CommitDiff failed: %v", err)
+	}
+	var found bool
+	for _, f := range files {
+		if f.Path == "README.md" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected README.md in diff, got %+v", files)
+	}
+}
+
+// TestCountCommits tests counting commits reachable from "to" but not
+// "from".
+func TestCountCommits(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	root, _ := repo.CurrentCommit()
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Second commit\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Second commit"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	head, _ := repo.CurrentCommit()
+
+	count, err := repo.CountCommits(root, head)
+	if err != nil {
+		t.Fatalf("CountCommits failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 commit between root and head, got %d", count)
+	}
+}
+
+// TestLogRange tests that LogRange returns only the commits introduced by
+// from..to, newest first.
+func TestLogRange(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	root, _ := repo.CurrentCommit()
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Second commit\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Second commit"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	head, _ := repo.CurrentCommit()
+
+	commits, err := repo.LogRange(root, head, LogOptions{})
+	if err != nil {
+		t.Fatalf("LogRange failed: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("expected 1 commit in range, got %d: %+v", len(commits), commits)
+	}
+	if commits[0].Subject != "Second commit" {
+		t.Errorf("expected %q, got %q", "Second commit", commits[0].Subject)
+	}
+}
+
+// TestComputeGraph_Merge tests that a merge commit opens a second column
+// for its non-first parent.
+func TestComputeGraph_Merge(t *testing.T) {
+	commits := []Commit{
+		{SHA: "merge", Parents: []string{"left2", "right2"}},
+		{SHA: "left2", Parents: []string{"base"}},
+		{SHA: "right2", Parents: []string{"base"}},
+		{SHA: "base", Parents: nil},
+	}
+
+	computeGraph(commits)
+
+	if commits[0].GraphColumn != 0 {
+		t.Errorf("expected merge commit in column 0, got %d", commits[0].GraphColumn)
+	}
+	if commits[1].GraphColumn != 0 {
+		t.Errorf("expected first parent to continue in column 0, got %d", commits[1].GraphColumn)
+	}
+	if commits[2].GraphColumn != 1 {
+		t.Errorf("expected second parent to open column 1, got %d", commits[2].GraphColumn)
+	}
+	for i, c := range commits {
+		if c.GraphRow != i {
+			t.Errorf("expected GraphRow %d, got %d", i, c.GraphRow)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// nativeRepo is the go-git backed Repository implementation. Branch and
+// commit lookups, file reads, Stage, Discard, the change checks and the
+// branch-mode diff run in-process, avoiding the ~50ms process-startup cost
+// of shelling out to `git`. Unstage, CommitWithOptions, the hunk/line
+// staging methods, GetWorkingTreeDiff, GetStagedDiff and commit-mode
+// GetDiff still run the `git` binary through the exec backend. Its
+// GetDiffFiles leaves Additions, Deletions and OldPath unset, and its
+// GetDiffWithContents attaches no file contents.
+type nativeRepo struct {
+	repo *gogit.Repository
+	path string
+}
+
+// openNative opens a repository at path using go-git instead of exec'ing git.
+func openNative(path string) (*nativeRepo, error) {
+	repo, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve worktree: %w", err)
+	}
+
+	return &nativeRepo{repo: repo, path: wt.Filesystem.Root()}, nil
+}
+
+func (r *nativeRepo) CurrentBranch() (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if head.Name().IsBranch() {
+		return head.Name().Short(), nil
+	}
+	return head.Hash().String(), nil
+}
+
+func (r *nativeRepo) CurrentCommit() (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current commit: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+func (r *nativeRepo) RepoPath() (string, error) {
+	return r.path, nil
+}
+
+func (r *nativeRepo) GetRemoteURL() (string, error) {
+	remote, err := r.repo.Remote("origin")
+	if err != nil {
+		return "", nil // No origin remote
+	}
+	cfg := remote.Config()
+	if len(cfg.URLs) == 0 {
+		return "", nil
+	}
+	return cfg.URLs[0], nil
+}
+
+func (r *nativeRepo) GetDefaultBranch() string {
+	ref, err := r.repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), true)
+	if err == nil && ref.Name().IsRemote() {
+		return strings.TrimPrefix(ref.Name().Short(), "origin/")
+	}
+
+	for _, branch := range []string{"main", "master", "develop", "development"} {
+		if _, err := r.repo.Reference(plumbing.NewBranchReferenceName(branch), true); err == nil {
+			return branch
+		}
+	}
+	return "main"
+}
+
+func (r *nativeRepo) HasUncommittedChanges() bool {
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return false
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return false
+	}
+	return !status.IsClean()
+}
+
+func (r *nativeRepo) HasStagedChanges() bool {
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return false
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return false
+	}
+	for _, s := range status {
+		if s.Staging != gogit.Unmodified {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *nativeRepo) Stage(filePath string) error {
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	_, err = wt.Add(filePath)
+	return err
+}
+
+// Unstage delegates to the exec backend: go-git's Worktree.Remove would
+// also delete the file from the working tree.
+func (r *nativeRepo) Unstage(filePath string) error {
+	return (&Repo{path: r.path}).Unstage(filePath)
+}
+
+func (r *nativeRepo) Discard(filePath string) error {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	entry, err := idx.Entry(filePath)
+	if err != nil {
+		// Not in the index: an untracked file, so discarding means
+		// removing it (mirrors the exec backend's `git clean -f` fallback).
+		return os.Remove(filepath.Join(r.path, filePath))
+	}
+
+	blob, err := r.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(r.path, filePath), data, 0644)
+}
+
+func (r *nativeRepo) Commit(message string) error {
+	return r.CommitWithOptions(message, CommitOptions{})
+}
+
+// CommitWithOptions falls back to the exec backend: go-git's CommitOptions
+// doesn't cover GPG signing or amending, so there's little to gain from
+// reimplementing this one in-process.
+func (r *nativeRepo) CommitWithOptions(message string, opts CommitOptions) error {
+	return (&Repo{path: r.path}).CommitWithOptions(message, opts)
+}
+
+// StageHunk, UnstageHunk, DiscardHunk, StageLines and DiscardLines apply a
+// synthesized unified diff. go-git has no in-process "apply patch" API, so
+// the native backend falls back to exec'ing `git apply` for just this one
+// operation rather than reimplementing a patch applier.
+func (r *nativeRepo) StageHunk(patch string) error   { return (&Repo{path: r.path}).StageHunk(patch) }
+func (r *nativeRepo) UnstageHunk(patch string) error { return (&Repo{path: r.path}).UnstageHunk(patch) }
+func (r *nativeRepo) DiscardHunk(patch string) error { return (&Repo{path: r.path}).DiscardHunk(patch) }
+func (r *nativeRepo) StageLines(patch string) error  { return (&Repo{path: r.path}).StageLines(patch) }
+func (r *nativeRepo) DiscardLines(patch string) error {
+	return (&Repo{path: r.path}).DiscardLines(patch)
+}
+
+func (r *nativeRepo) GetFileAtHEAD(filePath string) (string, error) {
+	return r.GetFileAtRef("HEAD", filePath)
+}
+
+func (r *nativeRepo) GetFileAtRef(ref, filePath string) (string, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", err
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return "", err
+	}
+	file, err := commit.File(filePath)
+	if err != nil {
+		return "", err
+	}
+	return file.Contents()
+}
+
+func (r *nativeRepo) GetFileFromIndex(filePath string) (string, error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return "", err
+	}
+	entry, err := idx.Entry(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s in index: %w", filePath, err)
+	}
+	blob, err := r.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return "", err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *nativeRepo) GetWorkingFile(filePath string) (string, error) {
+	data, err := os.ReadFile(r.path + string(os.PathSeparator) + filePath)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *nativeRepo) GetDiff(mode DiffMode, baseBranch string) ([]FileInfo, error) {
+	switch mode {
+	case DiffModeWorking:
+		return r.GetWorkingTreeDiff()
+	case DiffModeStaged:
+		return r.GetStagedDiff()
+	case DiffModeCommit:
+		return (&Repo{path: r.path}).GetCommitRangeDiff(baseBranch)
+	default:
+		return r.GetDiffFiles(baseBranch)
+	}
+}
+
+// GetWorkingTreeDiff delegates to the exec backend. go-git's Worktree.Status
+// only reports a per-path state code, not the additions/deletions and
+// unified patch text that FileInfo requires, and go-git has no in-process
+// equivalent of `git diff` against the working tree to produce one.
+func (r *nativeRepo) GetWorkingTreeDiff() ([]FileInfo, error) {
+	return (&Repo{path: r.path}).GetWorkingTreeDiff()
+}
+
+// GetStagedDiff delegates to the exec backend for the same reason as
+// GetWorkingTreeDiff: go-git can't produce a unified patch for staged
+// changes in-process.
+func (r *nativeRepo) GetStagedDiff() ([]FileInfo, error) {
+	return (&Repo{path: r.path}).GetStagedDiff()
+}
+
+func (r *nativeRepo) GetDiffFiles(baseBranch string) ([]FileInfo, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	baseHash, err := r.resolveBaseBranch(baseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base with %s: %w", baseBranch, err)
+	}
+	baseCommit, err := r.repo.CommitObject(*baseHash)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil || len(mergeBases) == 0 {
+		return nil, fmt.Errorf("failed to find merge base with %s: %w", baseBranch, err)
+	}
+
+	baseTree, err := mergeBases[0].Tree()
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := baseTree.Diff(headTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	files := []FileInfo{}
+	for _, change := range changes {
+		action, _ := change.Action()
+		patch, err := change.Patch()
+		patchStr := ""
+		if err == nil {
+			patchStr = patch.String()
+		}
+
+		status := "modified"
+		path := change.To.Name
+		switch action {
+		case merkletrie.Insert:
+			status = "added"
+		case merkletrie.Delete:
+			status = "deleted"
+			path = change.From.Name
+		}
+
+		files = append(files, FileInfo{
+			Path:   path,
+			Status: status,
+			Patch:  patchStr,
+		})
+	}
+	return files, nil
+}
+
+func (r *nativeRepo) GetDiffWithContents(mode DiffMode, baseBranch string) ([]FileInfo, error) {
+	return r.GetDiff(mode, baseBranch)
+}
+
+// resolveBaseBranch mirrors Repo.getMergeBase's ref resolution: try
+// origin/<baseBranch> first, then the local branch name.
+func (r *nativeRepo) resolveBaseBranch(baseBranch string) (*plumbing.Hash, error) {
+	for _, ref := range []string{"origin/" + baseBranch, baseBranch} {
+		if hash, err := r.repo.ResolveRevision(plumbing.Revision(ref)); err == nil {
+			return hash, nil
+		}
+	}
+	return nil, fmt.Errorf("could not resolve %s", baseBranch)
+}
@@ -0,0 +1,363 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// HunkLine is a single line within a hunk, tagged with the marker git uses
+// in unified diff output ('+', '-', or ' ' for context).
+type HunkLine struct {
+	Marker  byte
+	Content string
+	OldLine int // 0 if the line does not exist in the old file (added lines)
+	NewLine int // 0 if the line does not exist in the new file (removed lines)
+}
+
+// Hunk is a single `@@ ... @@` section of a patch.
+type Hunk struct {
+	Header   string // the trailing context after the second "@@", e.g. "func foo() {"
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []HunkLine
+}
+
+// Patch is a parsed single-file unified diff, split into its preamble
+// (the "diff --git"/"---"/"+++" header lines) and its hunks.
+type Patch struct {
+	Preamble string
+	Hunks    []Hunk
+}
+
+var hunkHeaderPattern = "@@ -%d,%d +%d,%d @@"
+
+// ParsePatch parses a single-file unified diff (as produced by `git diff`)
+// into a Patch, splitting the preamble from its hunks.
+func ParsePatch(diff string) (*Patch, error) {
+	lines := strings.Split(diff, "\n")
+
+	hunkStart := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@ ") {
+			hunkStart = i
+			break
+		}
+	}
+	if hunkStart == -1 {
+		return nil, fmt.Errorf("no hunks found in patch")
+	}
+
+	preamble := strings.Join(lines[:hunkStart], "\n") + "\n"
+
+	p := &Patch{Preamble: preamble}
+
+	var current *Hunk
+	for _, line := range lines[hunkStart:] {
+		if strings.HasPrefix(line, "@@ ") {
+			if current != nil {
+				p.Hunks = append(p.Hunks, *current)
+			}
+			hunk, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			current = hunk
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case '+':
+			current.Lines = append(current.Lines, HunkLine{Marker: '+', Content: line[1:]})
+		case '-':
+			current.Lines = append(current.Lines, HunkLine{Marker: '-', Content: line[1:]})
+		case ' ':
+			current.Lines = append(current.Lines, HunkLine{Marker: ' ', Content: line[1:]})
+		case '\\':
+			// "\ No newline at end of file" - ignore
+		default:
+			current.Lines = append(current.Lines, HunkLine{Marker: ' ', Content: line})
+		}
+	}
+	if current != nil {
+		p.Hunks = append(p.Hunks, *current)
+	}
+
+	for h := range p.Hunks {
+		annotateLineNumbers(&p.Hunks[h])
+	}
+
+	return p, nil
+}
+
+func parseHunkHeader(line string) (*Hunk, error) {
+	var oldStart, oldLines, newStart, newLines int
+	rest := strings.TrimPrefix(line, "@@ ")
+	parts := strings.SplitN(rest, " @@", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed hunk header: %q", line)
+	}
+	ranges := strings.Fields(parts[0])
+	if len(ranges) != 2 {
+		return nil, fmt.Errorf("malformed hunk header ranges: %q", line)
+	}
+
+	var err error
+	oldStart, oldLines, err = parseRange(ranges[0], '-')
+	if err != nil {
+		return nil, err
+	}
+	newStart, newLines, err = parseRange(ranges[1], '+')
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hunk{
+		Header:   strings.TrimPrefix(parts[1], " "),
+		OldStart: oldStart,
+		OldLines: oldLines,
+		NewStart: newStart,
+		NewLines: newLines,
+	}, nil
+}
+
+func parseRange(s string, prefix byte) (start, count int, err error) {
+	if len(s) == 0 || s[0] != prefix {
+		return 0, 0, fmt.Errorf("malformed hunk range: %q", s)
+	}
+	s = s[1:]
+	numParts := strings.SplitN(s, ",", 2)
+	start, err = strconv.Atoi(numParts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed hunk range: %q", s)
+	}
+	count = 1
+	if len(numParts) == 2 {
+		count, err = strconv.Atoi(numParts[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("malformed hunk range: %q", s)
+		}
+	}
+	return start, count, nil
+}
+
+// annotateLineNumbers fills in OldLine/NewLine for each line in the hunk
+// based on the hunk's starting offsets.
+func annotateLineNumbers(h *Hunk) {
+	oldLine := h.OldStart
+	newLine := h.NewStart
+	for i := range h.Lines {
+		switch h.Lines[i].Marker {
+		case ' ':
+			h.Lines[i].OldLine = oldLine
+			h.Lines[i].NewLine = newLine
+			oldLine++
+			newLine++
+		case '-':
+			h.Lines[i].OldLine = oldLine
+			oldLine++
+		case '+':
+			h.Lines[i].NewLine = newLine
+			newLine++
+		}
+	}
+}
+
+// ModifyPatchForHunk returns a standalone patch (preamble + single hunk)
+// containing only the hunk at hunkIdx, suitable for `git apply`.
+func ModifyPatchForHunk(p *Patch, hunkIdx int) string {
+	if hunkIdx < 0 || hunkIdx >= len(p.Hunks) {
+		return ""
+	}
+	return p.Preamble + renderHunk(p.Hunks[hunkIdx])
+}
+
+// ModifyPatchForLines returns a standalone patch (preamble + single hunk)
+// containing only the selected lines of the hunk at hunkIdx. Unselected
+// '+' lines are dropped entirely; unselected '-' lines are converted to
+// context so the rest of the hunk still applies cleanly. When reverse is
+// true, the roles of '+' and '-' are swapped (for discarding changes).
+func ModifyPatchForLines(p *Patch, hunkIdx int, lineIdxs []int, reverse bool) string {
+	if hunkIdx < 0 || hunkIdx >= len(p.Hunks) {
+		return ""
+	}
+	selected := make(map[int]bool, len(lineIdxs))
+	for _, idx := range lineIdxs {
+		selected[idx] = true
+	}
+
+	orig := p.Hunks[hunkIdx]
+	addMarker, removeMarker := byte('+'), byte('-')
+	if reverse {
+		addMarker, removeMarker = '-', '+'
+	}
+
+	newHunk := Hunk{Header: orig.Header}
+	for i, line := range orig.Lines {
+		switch line.Marker {
+		case ' ':
+			newHunk.Lines = append(newHunk.Lines, line)
+		case addMarker:
+			if selected[i] {
+				newHunk.Lines = append(newHunk.Lines, line)
+			}
+			// unselected additions are dropped entirely
+		case removeMarker:
+			if selected[i] {
+				newHunk.Lines = append(newHunk.Lines, line)
+			} else {
+				// unselected removals become context
+				newHunk.Lines = append(newHunk.Lines, HunkLine{Marker: ' ', Content: line.Content})
+			}
+		}
+	}
+
+	recomputeHunkHeader(&newHunk, orig)
+	return p.Preamble + renderHunk(newHunk)
+}
+
+// recomputeHunkHeader derives OldStart/NewStart/OldLines/NewLines for a
+// modified hunk from the surviving lines and the original hunk's offsets.
+func recomputeHunkHeader(h *Hunk, orig Hunk) {
+	h.OldStart = orig.OldStart
+	h.NewStart = orig.NewStart
+
+	oldLines, newLines := 0, 0
+	for _, line := range h.Lines {
+		switch line.Marker {
+		case ' ':
+			oldLines++
+			newLines++
+		case '-':
+			oldLines++
+		case '+':
+			newLines++
+		}
+	}
+	h.OldLines = oldLines
+	h.NewLines = newLines
+}
+
+func renderHunk(h Hunk) string {
+	var b bytes.Buffer
+	header := fmt.Sprintf(hunkHeaderPattern, h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	if h.Header != "" {
+		header += " " + h.Header
+	}
+	b.WriteString(header)
+	b.WriteByte('\n')
+	for _, line := range h.Lines {
+		b.WriteByte(line.Marker)
+		b.WriteString(line.Content)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// applyPatch runs `git apply` with the given patch fed on stdin.
+func (r *Repo) applyPatch(patch string, extraArgs ...string) error {
+	args := append([]string{"apply"}, extraArgs...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader(patch)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git apply failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// StageHunk stages a single hunk by applying it to the index.
+func (r *Repo) StageHunk(patch string) error {
+	return r.applyPatch(patch, "--cached")
+}
+
+// UnstageHunk removes a single hunk from the index.
+func (r *Repo) UnstageHunk(patch string) error {
+	return r.applyPatch(patch, "--cached", "--reverse")
+}
+
+// DiscardHunk reverts a single hunk in the working tree.
+func (r *Repo) DiscardHunk(patch string) error {
+	return r.applyPatch(patch, "--reverse")
+}
+
+// StageLines stages a patch built by ModifyPatchForLines against the index.
+func (r *Repo) StageLines(patch string) error {
+	return r.applyPatch(patch, "--cached")
+}
+
+// DiscardLines reverts a patch built by ModifyPatchForLines in the working tree.
+func (r *Repo) DiscardLines(patch string) error {
+	return r.applyPatch(patch, "--reverse")
+}
+
+// buildHunkPatch synthesizes a single-hunk unified diff for filePath from
+// explicit hunk coordinates and pre-marked lines (each already prefixed
+// with '+', '-', or ' '), for callers that have hunk data of their own
+// rather than a Patch parsed from GetWorkingTreeDiff/GetStagedDiff.
+func buildHunkPatch(filePath string, oldStart, oldCount, newStart, newCount int, lines []string) (string, error) {
+	gotOld, gotNew := 0, 0
+	for _, line := range lines {
+		if line == "" {
+			return "", fmt.Errorf("hunk line must start with '+', '-', or ' ', got empty line")
+		}
+		switch line[0] {
+		case '+':
+			gotNew++
+		case '-':
+			gotOld++
+		case ' ':
+			gotOld++
+			gotNew++
+		default:
+			return "", fmt.Errorf("hunk line must start with '+', '-', or ' ', got %q", line)
+		}
+	}
+	if gotOld != oldCount || gotNew != newCount {
+		return "", fmt.Errorf("hunk line counts don't match header: header says -%d,+%d, lines contain -%d,+%d",
+			oldCount, newCount, gotOld, gotNew)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", filePath, filePath)
+	fmt.Fprintf(&b, "--- a/%s\n", filePath)
+	fmt.Fprintf(&b, "+++ b/%s\n", filePath)
+	fmt.Fprintf(&b, hunkHeaderPattern+"\n", oldStart, oldCount, newStart, newCount)
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// StageLinesAt stages the given pre-marked lines of a single hunk directly
+// against the index, for callers building a hunk from raw coordinates (e.g.
+// a `{old_start, old_count, new_start, new_count, lines}` API payload)
+// instead of a Patch parsed from an existing diff.
+func (r *Repo) StageLinesAt(filePath string, oldStart, oldCount, newStart, newCount int, lines []string) error {
+	patch, err := buildHunkPatch(filePath, oldStart, oldCount, newStart, newCount, lines)
+	if err != nil {
+		return err
+	}
+	return r.applyPatch(patch, "--cached")
+}
+
+// UnstageLinesAt is the reverse of StageLinesAt: it removes the given
+// pre-marked lines of a single hunk from the index.
+func (r *Repo) UnstageLinesAt(filePath string, oldStart, oldCount, newStart, newCount int, lines []string) error {
+	patch, err := buildHunkPatch(filePath, oldStart, oldCount, newStart, newCount, lines)
+	if err != nil {
+		return err
+	}
+	return r.applyPatch(patch, "--cached", "--reverse")
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+const samplePatch = `diff --git a/foo.go b/foo.go
+index abc123..def456 100644
+--- a/foo.go
++++ b/foo.go
+@@ -1,3 +1,5 @@
+ package main
++import "fmt"
+ func main() {
++    fmt.Println("hi")
+ }
+`
+
+// TestParsePatch tests parsing a single-file unified diff into hunks.
+func TestParsePatch(t *testing.T) {
+	p, err := ParsePatch(samplePatch)
+	if err != nil {
+		t.Fatalf("ParsePatch failed: %v", err)
+	}
+
+	if len(p.Hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(p.Hunks))
+	}
+
+	hunk := p.Hunks[0]
+	if hunk.OldStart != 1 || hunk.OldLines != 3 || hunk.NewStart != 1 || hunk.NewLines != 5 {
+		t.Errorf("unexpected hunk header: %+v", hunk)
+	}
+	if len(hunk.Lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d", len(hunk.Lines))
+	}
+}
+
+// TestModifyPatchForLines tests selecting a subset of lines from a hunk.
+func TestModifyPatchForLines(t *testing.T) {
+	p, err := ParsePatch(samplePatch)
+	if err != nil {
+		t.Fatalf("ParsePatch failed: %v", err)
+	}
+
+	// Select only the first added line (index 1: "+import \"fmt\"")
+	out := ModifyPatchForLines(p, 0, []int{1}, false)
+
+	if out == "" {
+		t.Fatal("expected non-empty patch")
+	}
+	if !strings.Contains(out, "@@ -1,3 +1,4 @@") {
+		t.Errorf("expected header recomputed from the surviving lines, got:\n%s", out)
+	}
+	if strings.Contains(out, `fmt.Println("hi")`) {
+		t.Errorf("unselected addition should have been dropped:\n%s", out)
+	}
+}
+
+// TestBuildHunkPatch tests synthesizing a patch from raw hunk coordinates.
+func TestBuildHunkPatch(t *testing.T) {
+	lines := []string{" package main", "+import \"fmt\"", " func main() {", " }"}
+	patch, err := buildHunkPatch("foo.go", 1, 3, 1, 4, lines)
+	if err != nil {
+		t.Fatalf("buildHunkPatch failed: %v", err)
+	}
+	if !strings.Contains(patch, "@@ -1,3 +1,4 @@") {
+		t.Errorf("expected hunk header in output:\n%s", patch)
+	}
+	if !strings.Contains(patch, "+import \"fmt\"") {
+		t.Errorf("expected added line in output:\n%s", patch)
+	}
+}
+
+// TestBuildHunkPatch_CountMismatch tests that mismatched line counts are rejected.
+func TestBuildHunkPatch_CountMismatch(t *testing.T) {
+	lines := []string{" package main", "+import \"fmt\""}
+	if _, err := buildHunkPatch("foo.go", 1, 3, 1, 4, lines); err == nil {
+		t.Fatal("expected error for mismatched hunk line counts")
+	}
+}
@@ -0,0 +1,273 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Signature identifies the author or committer of a commit.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// RebaseOpType identifies the kind of step a rebase performs on a commit.
+type RebaseOpType string
+
+const (
+	RebaseOpPick   RebaseOpType = "pick"
+	RebaseOpEdit   RebaseOpType = "edit"
+	RebaseOpSquash RebaseOpType = "squash"
+	RebaseOpReword RebaseOpType = "reword"
+	RebaseOpFixup  RebaseOpType = "fixup"
+	RebaseOpDrop   RebaseOpType = "drop"
+)
+
+// RebaseOperation is a single line of a rebase todo list.
+type RebaseOperation struct {
+	Type    RebaseOpType
+	SHA     string
+	Subject string
+}
+
+// RebaseOptions configures a rebase started with StartRebase.
+type RebaseOptions struct {
+	// Operations, if non-empty, replaces git's generated todo list entirely.
+	// Every commit the caller wants touched (including plain picks) must
+	// be listed explicitly.
+	Operations []RebaseOperation
+}
+
+// Rebase is a handle onto an in-progress rebase, modeled after git2go's
+// Rebase API. The exec backend has no in-process state machine, so it
+// drives `git rebase --interactive` and polls .git/rebase-merge/ for
+// progress instead of stepping through libgit2 operations directly.
+type Rebase struct {
+	repo *Repo
+}
+
+// StartRebase begins an interactive rebase onto upstream. If opts.Operations
+// is set, it is written out as the rebase todo list via a GIT_SEQUENCE_EDITOR
+// shim, overriding git's default pick-everything todo.
+func (r *Repo) StartRebase(upstream string, opts RebaseOptions) (*Rebase, error) {
+	cmd := exec.Command("git", "rebase", "--interactive", upstream)
+	cmd.Dir = r.path
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+
+	if len(opts.Operations) > 0 {
+		todoFile, err := writeTodoFile(opts.Operations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write rebase todo: %w", err)
+		}
+		defer os.Remove(todoFile)
+
+		cmd.Env = append(cmd.Env, fmt.Sprintf(
+			"GIT_SEQUENCE_EDITOR=bash -c 'cat %q > \"$1\"' --", todoFile,
+		))
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		// A conflict or an "edit"/"reword" stop also exits non-zero; only
+		// treat it as fatal if the rebase never actually started.
+		if !r.RebaseInProgress() {
+			return nil, fmt.Errorf("failed to start rebase: %w: %s", err, out)
+		}
+	}
+
+	return &Rebase{repo: r}, nil
+}
+
+func writeTodoFile(ops []RebaseOperation) (string, error) {
+	f, err := os.CreateTemp("", "cerebro-rebase-todo-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for _, op := range ops {
+		fmt.Fprintf(&b, "%s %s %s\n", op.Type, op.SHA, op.Subject)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func (r *Repo) rebaseMergeDir() string {
+	return filepath.Join(r.path, ".git", "rebase-merge")
+}
+
+// RebaseInProgress reports whether a rebase is currently stopped mid-flight.
+func (r *Repo) RebaseInProgress() bool {
+	_, err := os.Stat(r.rebaseMergeDir())
+	return err == nil
+}
+
+// RebaseStatus describes the current state of an in-progress rebase.
+type RebaseStatus struct {
+	CurrentOp  RebaseOpType
+	TargetSHA  string
+	Conflicted []string
+}
+
+// RebaseStatus reports the current operation, target commit, and any
+// conflicted paths of an in-progress rebase.
+func (r *Repo) RebaseStatus() (*RebaseStatus, error) {
+	dir := r.rebaseMergeDir()
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("no rebase in progress")
+	}
+
+	status := &RebaseStatus{}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "stopped-sha")); err == nil {
+		status.TargetSHA = strings.TrimSpace(string(data))
+	}
+
+	// The last line of "done" is the step the rebase is stopped at;
+	// git-rebase-todo holds only the steps still pending after it.
+	if data, err := os.ReadFile(filepath.Join(dir, "done")); err == nil {
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if last := lines[len(lines)-1]; last != "" {
+			fields := strings.Fields(last)
+			if len(fields) >= 1 {
+				status.CurrentOp = RebaseOpType(fields[0])
+			}
+			if len(fields) >= 2 && status.TargetSHA == "" {
+				status.TargetSHA = fields[1]
+			}
+		}
+	}
+
+	conflicted, err := r.conflictedPaths()
+	if err == nil {
+		status.Conflicted = conflicted
+	}
+
+	return status, nil
+}
+
+// conflictedPaths returns paths with unmerged entries per
+// `git status --porcelain=v2`.
+func (r *Repo) conflictedPaths() ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain=v2")
+	cmd.Dir = r.path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicted []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "u ") { // unmerged entry
+			fields := strings.Fields(line)
+			if len(fields) > 0 {
+				conflicted = append(conflicted, fields[len(fields)-1])
+			}
+		}
+	}
+	return conflicted, nil
+}
+
+// Next reports the operation the rebase is currently stopped at, returning
+// nil once the rebase has completed. It does not advance the rebase;
+// Finish does.
+func (rb *Rebase) Next() (*RebaseOperation, error) {
+	status, err := rb.repo.RebaseStatus()
+	if err != nil {
+		// rebase-merge directory is gone: the rebase has finished
+		return nil, nil
+	}
+	if status.CurrentOp == "" {
+		return nil, nil
+	}
+	return &RebaseOperation{Type: status.CurrentOp, SHA: status.TargetSHA}, nil
+}
+
+// Commit amends the currently stopped commit with the given author,
+// committer, and message, for use during an "edit" or "reword" stop.
+func (rb *Rebase) Commit(author, committer Signature, message string) error {
+	cmd := exec.Command("git", "commit", "--amend", "-m", message)
+	cmd.Dir = rb.repo.path
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+author.Name,
+		"GIT_AUTHOR_EMAIL="+author.Email,
+		"GIT_COMMITTER_NAME="+committer.Name,
+		"GIT_COMMITTER_EMAIL="+committer.Email,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to amend commit during rebase: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Finish continues the rebase until it completes or hits another stop.
+func (rb *Rebase) Finish() error {
+	cmd := exec.Command("git", "rebase", "--continue")
+	cmd.Dir = rb.repo.path
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if rb.repo.RebaseInProgress() {
+			return nil // stopped again (edit/conflict); caller should poll Next()
+		}
+		return fmt.Errorf("failed to continue rebase: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Abort cancels the rebase and restores the pre-rebase HEAD.
+func (rb *Rebase) Abort() error {
+	cmd := exec.Command("git", "rebase", "--abort")
+	cmd.Dir = rb.repo.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort rebase: %w: %s", err, out)
+	}
+	return nil
+}
+
+// CherryPick applies the changes introduced by each of hashes, in order,
+// as new commits onto the current branch.
+func (r *Repo) CherryPick(hashes ...string) error {
+	args := append([]string{"cherry-pick"}, hashes...)
+	out, err := r.NewCmd(args...).WithEnv("GIT_EDITOR=true").RunWithOutput()
+	if err != nil {
+		return fmt.Errorf("failed to cherry-pick %s: %w: %s", strings.Join(hashes, ", "), err, out)
+	}
+	return nil
+}
+
+// Revert creates a new commit that undoes the changes introduced by hash.
+func (r *Repo) Revert(hash string) error {
+	out, err := r.NewCmd("revert", hash).WithEnv("GIT_EDITOR=true").RunWithOutput()
+	if err != nil {
+		return fmt.Errorf("failed to revert %s: %w: %s", hash, err, out)
+	}
+	return nil
+}
+
+// CherryPickContinue resumes a cherry-pick after conflicts are resolved.
+func (r *Repo) CherryPickContinue() error {
+	cmd := exec.Command("git", "cherry-pick", "--continue")
+	cmd.Dir = r.path
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to continue cherry-pick: %w: %s", err, out)
+	}
+	return nil
+}
+
+// CherryPickAbort cancels an in-progress cherry-pick.
+func (r *Repo) CherryPickAbort() error {
+	cmd := exec.Command("git", "cherry-pick", "--abort")
+	cmd.Dir = r.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort cherry-pick: %w: %s", err, out)
+	}
+	return nil
+}
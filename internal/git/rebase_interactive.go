@@ -0,0 +1,164 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseActionKind identifies the kind of step a RebaseAction performs on
+// a commit, matching the verbs accepted by a rebase todo file.
+type RebaseActionKind string
+
+const (
+	RebaseActionPick   RebaseActionKind = "pick"
+	RebaseActionReword RebaseActionKind = "reword"
+	RebaseActionEdit   RebaseActionKind = "edit"
+	RebaseActionSquash RebaseActionKind = "squash"
+	RebaseActionFixup  RebaseActionKind = "fixup"
+	RebaseActionDrop   RebaseActionKind = "drop"
+	RebaseActionExec   RebaseActionKind = "exec"
+)
+
+// RebaseAction is a single line of an interactive rebase todo list. Hash
+// identifies the commit for every kind except Exec, where Payload holds
+// the shell command to run and Hash is ignored.
+type RebaseAction struct {
+	Kind    RebaseActionKind
+	Hash    string
+	Payload string
+}
+
+// writeActionsTodoFile renders actions as a git rebase todo list in a temp
+// file, for use as the source a GIT_SEQUENCE_EDITOR shim copies from
+// (mirrors writeTodoFile's approach for RebaseOperation).
+func writeActionsTodoFile(actions []RebaseAction) (string, error) {
+	f, err := os.CreateTemp("", "cerebro-rebase-actions-todo-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for _, a := range actions {
+		if a.Kind == RebaseActionExec {
+			fmt.Fprintf(&b, "exec %s\n", a.Payload)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", a.Kind, a.Hash, a.Payload)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// InteractiveRebase starts a rebase onto baseRef, replacing git's generated
+// todo list with actions verbatim via the same GIT_SEQUENCE_EDITOR shim
+// StartRebase uses. Reword actions apply their Payload as the new commit
+// message automatically, via a GIT_EDITOR shim that feeds each queued
+// message to the corresponding reword stop in order (see
+// writeRewordEditorScript) — the rebase does not pause for them. An Edit
+// or Exec action (or a conflict) still stops the rebase mid-flight; poll
+// RebaseState() and call ContinueRebase or AbortRebase to proceed.
+func (r *Repo) InteractiveRebase(baseRef string, actions []RebaseAction) error {
+	todoFile, err := writeActionsTodoFile(actions)
+	if err != nil {
+		return fmt.Errorf("failed to write rebase todo: %w", err)
+	}
+	defer os.Remove(todoFile)
+
+	editorEnv, cleanupEditor, err := writeRewordEditorScript(actions)
+	if err != nil {
+		return fmt.Errorf("failed to prepare reword messages: %w", err)
+	}
+	defer cleanupEditor()
+
+	out, err := r.NewCmd("rebase", "--interactive", baseRef).WithEnv(
+		editorEnv,
+		fmt.Sprintf("GIT_SEQUENCE_EDITOR=bash -c 'cat %q > \"$1\"' --", todoFile),
+	).RunWithOutput()
+	if err != nil {
+		// A conflict, or an edit/exec stop, also exits non-zero; only
+		// treat it as fatal if the rebase never actually started.
+		if !r.RebaseInProgress() {
+			return fmt.Errorf("failed to start interactive rebase: %w: %s", err, out)
+		}
+	}
+	return nil
+}
+
+// writeRewordEditorScript writes each Reword action's Payload to its own
+// file in a temp directory, plus a GIT_EDITOR shim that, on each
+// invocation, copies the next queued message over the commit message git
+// prefilled (tracking position via a counter file).
+//
+// Reword invokes the editor once per action. A contiguous run of Squash
+// and/or Fixup actions invokes the editor at most once for the whole run
+// (to edit their combined message) — and only if the run contains at
+// least one Squash; a run of Fixup alone never opens the editor. The
+// counter therefore advances once per Reword and once per such run, not
+// once per action, mirroring exactly how many times git will actually
+// invoke GIT_EDITOR. Runs have no message file, so the shim leaves git's
+// prefilled (concatenated) message alone for them. Returns the
+// "GIT_EDITOR=..." env entry for WithEnv and a cleanup func to remove the
+// temp directory.
+func writeRewordEditorScript(actions []RebaseAction) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "cerebro-rebase-reword-*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	isSquashRun := func(k RebaseActionKind) bool {
+		return k == RebaseActionSquash || k == RebaseActionFixup
+	}
+
+	var n int
+	for i := 0; i < len(actions); i++ {
+		a := actions[i]
+		switch {
+		case a.Kind == RebaseActionReword:
+			msgFile := filepath.Join(dir, fmt.Sprintf("msg-%d", n))
+			if err := os.WriteFile(msgFile, []byte(a.Payload), 0644); err != nil {
+				cleanup()
+				return "", func() {}, err
+			}
+			n++
+
+		case isSquashRun(a.Kind):
+			if i > 0 && isSquashRun(actions[i-1].Kind) {
+				continue // mid-run: already accounted for at the run's start
+			}
+			var hasSquash bool
+			for j := i; j < len(actions) && isSquashRun(actions[j].Kind); j++ {
+				if actions[j].Kind == RebaseActionSquash {
+					hasSquash = true
+				}
+			}
+			if hasSquash {
+				n++
+			}
+		}
+	}
+
+	counterFile := filepath.Join(dir, "counter")
+	if err := os.WriteFile(counterFile, []byte("0"), 0644); err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	script := fmt.Sprintf(
+		`n=$(cat %q); f=%q/msg-$n; if [ -f "$f" ]; then cat "$f" > "$1"; fi; echo $((n+1)) > %q`,
+		counterFile, dir, counterFile,
+	)
+	return fmt.Sprintf("GIT_EDITOR=bash -c '%s' --", script), cleanup, nil
+}
+
+// RebaseState reports the current step and any conflicted paths of an
+// in-progress rebase, for prompting the user before calling ContinueRebase
+// or AbortRebase.
+func (r *Repo) RebaseState() (*RebaseStatus, error) {
+	return r.RebaseStatus()
+}
@@ -0,0 +1,251 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestInteractiveRebase_Squash tests squashing two commits into one via an
+// explicit action list.
+func TestInteractiveRebase_Squash(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	root, _ := repo.CurrentCommit()
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# First\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("First change"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	first, _ := repo.CurrentCommit()
+
+	if err := os.WriteFile(readme, []byte("# Second\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Second change"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	second, _ := repo.CurrentCommit()
+
+	actions := []RebaseAction{
+		{Kind: RebaseActionPick, Hash: first, Payload: "First change"},
+		{Kind: RebaseActionSquash, Hash: second, Payload: "Second change"},
+	}
+	if err := repo.InteractiveRebase(root, actions); err != nil {
+		t.Fatalf("InteractiveRebase failed: %v", err)
+	}
+
+	if repo.RebaseInProgress() {
+		state, _ := repo.RebaseState()
+		t.Fatalf("expected rebase to complete, still in progress: %+v", state)
+	}
+
+	commits, err := repo.Commits(LogOptions{})
+	if err != nil {
+		t.Fatalf("Commits failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected root + 1 squashed commit, got %d", len(commits))
+	}
+}
+
+// TestInteractiveRebase_SquashRunThenReword tests [pick, squash, squash,
+// reword]: the editor is invoked once for the two-commit squash run and
+// once more for the trailing reword, and the reword message must still
+// land on the right commit despite the squash run only contributing one
+// editor call for two actions.
+func TestInteractiveRebase_SquashRunThenReword(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	root, _ := repo.CurrentCommit()
+
+	writeAndCommit := func(content, message string) string {
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to modify README: %v", err)
+		}
+		if err := repo.Stage("README.md"); err != nil {
+			t.Fatalf("Stage failed: %v", err)
+		}
+		if err := repo.Commit(message); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		sha, _ := repo.CurrentCommit()
+		return sha
+	}
+
+	first := writeAndCommit("# First\n", "First change")
+	second := writeAndCommit("# Second\n", "Second change")
+	third := writeAndCommit("# Third\n", "Third change")
+	fourth := writeAndCommit("# Fourth\n", "Fourth change")
+
+	actions := []RebaseAction{
+		{Kind: RebaseActionPick, Hash: first, Payload: "First change"},
+		{Kind: RebaseActionSquash, Hash: second, Payload: "Second change"},
+		{Kind: RebaseActionSquash, Hash: third, Payload: "Third change"},
+		{Kind: RebaseActionReword, Hash: fourth, Payload: "Reworded fourth"},
+	}
+	if err := repo.InteractiveRebase(root, actions); err != nil {
+		t.Fatalf("InteractiveRebase failed: %v", err)
+	}
+
+	if repo.RebaseInProgress() {
+		state, _ := repo.RebaseState()
+		t.Fatalf("expected rebase to complete, still in progress: %+v", state)
+	}
+
+	commits, err := repo.Commits(LogOptions{})
+	if err != nil {
+		t.Fatalf("Commits failed: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("expected root + 1 squashed commit + 1 reworded commit, got %d: %+v", len(commits), commits)
+	}
+	if commits[0].Subject != "Reworded fourth" {
+		t.Errorf("expected HEAD's subject to be %q, got %q", "Reworded fourth", commits[0].Subject)
+	}
+}
+
+// TestInteractiveRebase_Reword tests that a Reword action's Payload is
+// applied as the commit's new message without the rebase stopping.
+func TestInteractiveRebase_Reword(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	root, _ := repo.CurrentCommit()
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# First\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Original message"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	first, _ := repo.CurrentCommit()
+
+	actions := []RebaseAction{
+		{Kind: RebaseActionReword, Hash: first, Payload: "Reworded message"},
+	}
+	if err := repo.InteractiveRebase(root, actions); err != nil {
+		t.Fatalf("InteractiveRebase failed: %v", err)
+	}
+
+	if repo.RebaseInProgress() {
+		state, _ := repo.RebaseState()
+		t.Fatalf("expected reword to complete without stopping, still in progress: %+v", state)
+	}
+
+	commits, err := repo.Commits(LogOptions{})
+	if err != nil {
+		t.Fatalf("Commits failed: %v", err)
+	}
+	if len(commits) == 0 || commits[0].Subject != "Reworded message" {
+		t.Fatalf("expected HEAD's subject to be %q, got commits: %+v", "Reworded message", commits)
+	}
+}
+
+// TestCherryPick_Multiple tests cherry-picking several commits in one call.
+func TestCherryPick_Multiple(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+
+	if err := repo.CheckoutNew("feature", ""); err != nil {
+		t.Fatalf("CheckoutNew failed: %v", err)
+	}
+
+	writeAndCommit := func(name, content, message string) string {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if err := repo.Stage(name); err != nil {
+			t.Fatalf("Stage failed: %v", err)
+		}
+		if err := repo.Commit(message); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		sha, _ := repo.CurrentCommit()
+		return sha
+	}
+
+	sha1 := writeAndCommit("a.txt", "a\n", "Add a")
+	sha2 := writeAndCommit("b.txt", "b\n", "Add b")
+
+	if err := repo.Checkout(base); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if err := repo.CherryPick(sha1, sha2); err != nil {
+		t.Fatalf("CherryPick failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Errorf("expected a.txt to exist: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Errorf("expected b.txt to exist: %v", err)
+	}
+}
+
+// TestRevert tests reverting a commit's changes.
+func TestRevert(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	newFile := filepath.Join(dir, "newfile.txt")
+	if err := os.WriteFile(newFile, []byte("content\n"), 0644); err != nil {
+		t.Fatalf("failed to write newfile.txt: %v", err)
+	}
+	if err := repo.Stage("newfile.txt"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Commit("Add newfile"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	sha, _ := repo.CurrentCommit()
+
+	if err := repo.Revert(sha); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+
+	if _, err := os.Stat(newFile); !os.IsNotExist(err) {
+		t.Errorf("expected newfile.txt to be removed by Revert(), stat err: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Stash is a single entry in the stash list.
+type Stash struct {
+	Index   int
+	Ref     string // e.g. "stash@{0}"
+	Hash    string
+	Subject string
+	RelDate string
+}
+
+// stashLogDelimiter separates the fields of a `git stash list` record.
+const stashLogDelimiter = "\x1f"
+
+// stashLogFormat produces one record per stash entry: ref, short hash,
+// subject, relative date - each separated by stashLogDelimiter.
+const stashLogFormat = "%gd" + stashLogDelimiter + "%h" + stashLogDelimiter + "%s" + stashLogDelimiter + "%cr"
+
+// Stashes returns the repository's stash list, most recent first.
+func (r *Repo) Stashes() ([]Stash, error) {
+	out, err := r.NewCmd("stash", "list", "--pretty=format:"+stashLogFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return parseStashList(out), nil
+}
+
+func parseStashList(output string) []Stash {
+	var stashes []Stash
+	for i, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, stashLogDelimiter)
+		if len(fields) < 4 {
+			continue
+		}
+		stashes = append(stashes, Stash{
+			Index:   i,
+			Ref:     fields[0],
+			Hash:    fields[1],
+			Subject: fields[2],
+			RelDate: fields[3],
+		})
+	}
+	return stashes
+}
+
+// stashRef returns the "stash@{N}" ref for a stash index.
+func stashRef(index int) string {
+	return "stash@{" + strconv.Itoa(index) + "}"
+}
+
+// StashSave stashes the current working tree and index changes with the
+// given message. If includeUntracked is true, untracked files are stashed
+// as well (via --include-untracked).
+func (r *Repo) StashSave(message string, includeUntracked bool) error {
+	args := []string{"stash", "push"}
+	if includeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	if out, err := r.NewCmd(args...).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to stash changes: %w: %s", err, out)
+	}
+	return nil
+}
+
+// StashApply applies the stash at index without removing it from the
+// stash list.
+func (r *Repo) StashApply(index int) error {
+	if out, err := r.NewCmd("stash", "apply", stashRef(index)).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to apply stash %d: %w: %s", index, err, out)
+	}
+	return nil
+}
+
+// StashPop applies the stash at index and removes it from the stash list.
+func (r *Repo) StashPop(index int) error {
+	if out, err := r.NewCmd("stash", "pop", stashRef(index)).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to pop stash %d: %w: %s", index, err, out)
+	}
+	return nil
+}
+
+// StashDrop removes the stash at index from the stash list without
+// applying it.
+func (r *Repo) StashDrop(index int) error {
+	if out, err := r.NewCmd("stash", "drop", stashRef(index)).RunWithOutput(); err != nil {
+		return fmt.Errorf("failed to drop stash %d: %w: %s", index, err, out)
+	}
+	return nil
+}
+
+// StashShow returns the file changes introduced by the stash at index.
+func (r *Repo) StashShow(index int) ([]FileInfo, error) {
+	args := append([]string{"stash", "show", "-p", "--no-color", stashRef(index)}, r.renameDetectionArgs()...)
+	out, err := r.NewCmd(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to show stash %d: %w", index, err)
+	}
+	return parseDiffOutput(out), nil
+}
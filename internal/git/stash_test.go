@@ -0,0 +1,144 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestStashSaveAndList tests stashing changes and listing the stash.
+func TestStashSaveAndList(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Stashed change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+
+	if err := repo.StashSave("my stash", false); err != nil {
+		t.Fatalf("StashSave failed: %v", err)
+	}
+
+	if repo.HasUncommittedChanges() {
+		t.Error("expected no uncommitted changes after StashSave()")
+	}
+
+	stashes, err := repo.Stashes()
+	if err != nil {
+		t.Fatalf("Stashes failed: %v", err)
+	}
+	if len(stashes) != 1 {
+		t.Fatalf("expected 1 stash, got %d", len(stashes))
+	}
+	if stashes[0].Index != 0 {
+		t.Errorf("expected index 0, got %d", stashes[0].Index)
+	}
+	if stashes[0].Subject == "" {
+		t.Error("expected a non-empty stash subject")
+	}
+}
+
+// TestStashPop tests applying and removing a stash.
+func TestStashPop(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Stashed change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.StashSave("my stash", false); err != nil {
+		t.Fatalf("StashSave failed: %v", err)
+	}
+
+	if err := repo.StashPop(0); err != nil {
+		t.Fatalf("StashPop failed: %v", err)
+	}
+
+	if !repo.HasUncommittedChanges() {
+		t.Error("expected uncommitted changes restored after StashPop()")
+	}
+
+	stashes, err := repo.Stashes()
+	if err != nil {
+		t.Fatalf("Stashes failed: %v", err)
+	}
+	if len(stashes) != 0 {
+		t.Errorf("expected 0 stashes after pop, got %d", len(stashes))
+	}
+}
+
+// TestStashDrop tests discarding a stash without applying it.
+func TestStashDrop(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Stashed change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.StashSave("my stash", false); err != nil {
+		t.Fatalf("StashSave failed: %v", err)
+	}
+
+	if err := repo.StashDrop(0); err != nil {
+		t.Fatalf("StashDrop failed: %v", err)
+	}
+
+	stashes, err := repo.Stashes()
+	if err != nil {
+		t.Fatalf("Stashes failed: %v", err)
+	}
+	if len(stashes) != 0 {
+		t.Errorf("expected 0 stashes after drop, got %d", len(stashes))
+	}
+	if repo.HasUncommittedChanges() {
+		t.Error("expected no uncommitted changes after dropping a stash")
+	}
+}
+
+// TestStashShow tests retrieving the file changes a stash introduces.
+func TestStashShow(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Stashed change\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := repo.StashSave("my stash", false); err != nil {
+		t.Fatalf("StashSave failed: %v", err)
+	}
+
+	files, err := repo.StashShow(0)
+	if err != nil {
+		t.Fatalf("StashShow failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	if files[0].Path != "README.md" {
+		t.Errorf("expected README.md, got %q", files[0].Path)
+	}
+}
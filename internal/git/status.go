@@ -0,0 +1,145 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileEntry is a single parsed `git status --porcelain=v2` record: the
+// authoritative source for rename/copy pairs and submodule state that
+// parseDiffOutput's best-effort diff-header scan cannot reliably recover
+// (it mishandles renames, copies, and patches whose context lines happen
+// to contain strings like "new file mode").
+type FileEntry struct {
+	Path    string
+	OldPath string // set for renames and copies
+	Score   string // similarity score for renames/copies, e.g. "R100" or "C87"
+
+	IndexState byte // status in the index (staged side), e.g. 'M', 'A', 'D', 'R', ' '
+	WorkState  byte // status in the working tree (unstaged side), e.g. 'M', 'D', ' '
+
+	// Submodule holds the raw 4-character submodule state field (e.g.
+	// "N..." for a non-submodule entry, "SC.M" for a submodule with a
+	// changed commit and tracked changes). Empty only if status didn't
+	// report one, which shouldn't happen for "1"/"2"/"u" entries.
+	Submodule string
+
+	Untracked bool
+	Ignored   bool
+	Unmerged  bool
+}
+
+// Status returns the repository's file status via `git status
+// --porcelain=v2`, including untracked files and rename/copy detection.
+func (r *Repo) Status() ([]FileEntry, error) {
+	out, err := r.NewCmd("status", "--porcelain=v2", "-z", "--untracked-files=all", "--renames").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	return parseStatusV2(out), nil
+}
+
+// parseStatusV2 parses the NUL-delimited records of `git status
+// --porcelain=v2 -z`. Renamed/copied ("2") records span two consecutive
+// NUL-terminated tokens: the header+new-path, then the old path.
+func parseStatusV2(output string) []FileEntry {
+	tokens := strings.Split(output, "\x00")
+	var entries []FileEntry
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok == "" {
+			continue
+		}
+
+		switch tok[0] {
+		case '1':
+			fields := strings.SplitN(tok, " ", 9)
+			if len(fields) < 9 || len(fields[1]) < 2 {
+				continue
+			}
+			entries = append(entries, FileEntry{
+				Path:       fields[8],
+				IndexState: fields[1][0],
+				WorkState:  fields[1][1],
+				Submodule:  fields[2],
+			})
+
+		case '2':
+			fields := strings.SplitN(tok, " ", 10)
+			if len(fields) < 10 || len(fields[1]) < 2 {
+				continue
+			}
+			var origPath string
+			if i+1 < len(tokens) {
+				i++
+				origPath = tokens[i]
+			}
+			entries = append(entries, FileEntry{
+				Path:       fields[9],
+				OldPath:    origPath,
+				Score:      fields[8],
+				IndexState: fields[1][0],
+				WorkState:  fields[1][1],
+				Submodule:  fields[2],
+			})
+
+		case 'u':
+			fields := strings.SplitN(tok, " ", 11)
+			if len(fields) < 11 || len(fields[1]) < 2 {
+				continue
+			}
+			entries = append(entries, FileEntry{
+				Path:       fields[10],
+				IndexState: fields[1][0],
+				WorkState:  fields[1][1],
+				Submodule:  fields[2],
+				Unmerged:   true,
+			})
+
+		case '?':
+			entries = append(entries, FileEntry{
+				Path:      strings.TrimPrefix(tok, "? "),
+				Untracked: true,
+			})
+
+		case '!':
+			entries = append(entries, FileEntry{
+				Path:    strings.TrimPrefix(tok, "! "),
+				Ignored: true,
+			})
+		}
+	}
+
+	return entries
+}
+
+// mergeStatusInfo overlays authoritative rename and submodule info from
+// Status() onto files parsed from a diff (parseDiffOutput), correcting
+// renamed/copied OldPath and flagging submodule entries so callers don't
+// have to trust diff-header string matching for either.
+func mergeStatusInfo(files []FileInfo, entries []FileEntry) {
+	byPath := make(map[string]FileEntry, len(entries))
+	for _, e := range entries {
+		byPath[e.Path] = e
+	}
+
+	for i := range files {
+		entry, ok := byPath[files[i].Path]
+		if !ok {
+			continue
+		}
+		if entry.OldPath != "" {
+			files[i].OldPath = entry.OldPath
+			if strings.HasPrefix(entry.Score, "C") {
+				files[i].Status = "copied"
+			} else {
+				files[i].Status = "renamed"
+			}
+		}
+		if entry.Submodule != "" && entry.Submodule[0] == 'S' {
+			files[i].Status = "submodule"
+			files[i].Submodule = entry.Submodule
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseStatusV2_OrdinaryAndUntracked tests parsing a mix of ordinary
+// changed, untracked, and ignored entries.
+func TestParseStatusV2_OrdinaryAndUntracked(t *testing.T) {
+	output := "1 M. N... 100644 100644 100644 abc123 abc123 foo.go\x00" +
+		"? newfile.txt\x00" +
+		"! ignored.log\x00"
+
+	entries := parseStatusV2(output)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	if entries[0].Path != "foo.go" || entries[0].IndexState != 'M' || entries[0].WorkState != '.' {
+		t.Errorf("unexpected ordinary entry: %+v", entries[0])
+	}
+	if entries[1].Path != "newfile.txt" || !entries[1].Untracked {
+		t.Errorf("unexpected untracked entry: %+v", entries[1])
+	}
+	if entries[2].Path != "ignored.log" || !entries[2].Ignored {
+		t.Errorf("unexpected ignored entry: %+v", entries[2])
+	}
+}
+
+// TestParseStatusV2_Rename tests parsing a rename record, which spans two
+// NUL-terminated tokens (header+new-path, then old path).
+func TestParseStatusV2_Rename(t *testing.T) {
+	output := "2 R. N... 100644 100644 100644 abc123 abc123 R100 new.go\x00old.go\x00"
+
+	entries := parseStatusV2(output)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Path != "new.go" {
+		t.Errorf("expected Path new.go, got %q", entries[0].Path)
+	}
+	if entries[0].OldPath != "old.go" {
+		t.Errorf("expected OldPath old.go, got %q", entries[0].OldPath)
+	}
+	if entries[0].Score != "R100" {
+		t.Errorf("expected Score R100, got %q", entries[0].Score)
+	}
+}
+
+// TestParseStatusV2_Submodule tests that submodule state chars are
+// captured on an ordinary entry.
+func TestParseStatusV2_Submodule(t *testing.T) {
+	output := "1 M. SC.M 160000 160000 160000 abc123 abc123 vendor/lib\x00"
+
+	entries := parseStatusV2(output)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Submodule != "SC.M" {
+		t.Errorf("expected Submodule SC.M, got %q", entries[0].Submodule)
+	}
+}
+
+// TestMergeStatusInfo_Rename tests that mergeStatusInfo overlays the
+// authoritative OldPath and status onto a diff-parsed FileInfo.
+func TestMergeStatusInfo_Rename(t *testing.T) {
+	files := []FileInfo{{Path: "new.go", Status: "modified"}}
+	entries := []FileEntry{{Path: "new.go", OldPath: "old.go", Score: "R100"}}
+
+	mergeStatusInfo(files, entries)
+
+	if files[0].Status != "renamed" {
+		t.Errorf("expected status renamed, got %q", files[0].Status)
+	}
+	if files[0].OldPath != "old.go" {
+		t.Errorf("expected OldPath old.go, got %q", files[0].OldPath)
+	}
+}
+
+// TestStatus tests Status() against a real repository.
+func TestStatus(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Modified\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatalf("failed to create untracked file: %v", err)
+	}
+
+	entries, err := repo.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+
+	var sawModified, sawUntracked bool
+	for _, e := range entries {
+		switch e.Path {
+		case "README.md":
+			sawModified = true
+		case "untracked.txt":
+			if !e.Untracked {
+				t.Errorf("expected untracked.txt to be marked Untracked")
+			}
+			sawUntracked = true
+		}
+	}
+	if !sawModified {
+		t.Error("expected README.md in status output")
+	}
+	if !sawUntracked {
+		t.Error("expected untracked.txt in status output")
+	}
+}
+
+// TestGetWorkingTreeDiff_RenameOldPath tests that GetWorkingTreeDiff
+// reports the pre-rename path via the authoritative status merge.
+func TestGetWorkingTreeDiff_RenameOldPath(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	oldPath := filepath.Join(dir, "README.md")
+	newPath := filepath.Join(dir, "RENAMED.md")
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatalf("failed to rename file: %v", err)
+	}
+	// git status only reports a rename once both sides of the move are staged.
+	if err := repo.Stage("README.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if err := repo.Stage("RENAMED.md"); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+
+	files, err := repo.GetWorkingTreeDiff()
+	if err != nil {
+		t.Fatalf("GetWorkingTreeDiff failed: %v", err)
+	}
+
+	var found bool
+	for _, f := range files {
+		if f.Path == "RENAMED.md" {
+			found = true
+			if f.Status != "renamed" {
+				t.Errorf("expected status renamed, got %q", f.Status)
+			}
+			if f.OldPath != "README.md" {
+				t.Errorf("expected OldPath README.md, got %q", f.OldPath)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected RENAMED.md in working tree diff")
+	}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tuist/guck/internal/git"
+)
+
+// AuthMode selects how incoming requests are authenticated.
+type AuthMode string
+
+const (
+	// AuthModeNone performs no authentication; every request is treated as
+	// coming from the "anonymous" principal. This is the default, matching
+	// guck's historical behavior of binding only to 127.0.0.1.
+	AuthModeNone AuthMode = "none"
+	// AuthModeToken requires an `Authorization: Bearer <token>` header
+	// matching AppState.AuthToken.
+	AuthModeToken AuthMode = "token"
+	// AuthModeBasic requires HTTP Basic auth whose credentials are
+	// resolved from .netrc / a configured cookiefile / the git credential
+	// helper chain, keyed on the repo's remote host.
+	AuthModeBasic AuthMode = "basic"
+	// AuthModeLocalUID trusts any peer whose SO_PEERCRED uid matches the
+	// uid the server is running as. Only meaningful when the listener is a
+	// Unix domain socket; see connContextKey.
+	AuthModeLocalUID AuthMode = "local-uid"
+)
+
+type contextKey string
+
+const (
+	principalContextKey contextKey = "principal"
+	connContextKey      contextKey = "conn"
+)
+
+// saveConnContext stashes the raw net.Conn for a request so later
+// middleware (local-uid auth) can inspect its peer credentials. Wire it up
+// via http.Server.ConnContext.
+func saveConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey, c)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in ctx by
+// authMiddleware, if any.
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalContextKey).(string)
+	return principal, ok
+}
+
+// authMiddleware enforces s.AuthMode on every request and, on success,
+// stores the resolved principal in the request context for handlers to use
+// in place of a hardcoded actor name.
+func (s *AppState) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="guck"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ctx := context.WithValue(r.Context(), principalContextKey, principal)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s *AppState) authenticate(r *http.Request) (string, bool) {
+	switch s.AuthMode {
+	case AuthModeToken:
+		return s.authenticateToken(r)
+	case AuthModeBasic:
+		return s.authenticateBasic(r)
+	case AuthModeLocalUID:
+		return s.authenticateLocalUID(r)
+	case AuthModeNone, "":
+		return "anonymous", true
+	default:
+		return "", false
+	}
+}
+
+func (s *AppState) authenticateToken(r *http.Request) (string, bool) {
+	if s.AuthToken == "" {
+		// Misconfigured: AuthMode is "token" but no token was set. Reject
+		// outright rather than letting an empty bearer token match.
+		return "", false
+	}
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	token := strings.TrimPrefix(header, prefix)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.AuthToken)) != 1 {
+		return "", false
+	}
+	return "bearer-token", true
+}
+
+func (s *AppState) authenticateBasic(r *http.Request) (string, bool) {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return "", false
+	}
+
+	host := remoteHost(s.RepoPath)
+	if host == "" {
+		return "", false
+	}
+
+	cred, ok := resolveCredential(s.RepoPath, host)
+	if !ok {
+		return "", false
+	}
+	if subtle.ConstantTimeCompare([]byte(user), []byte(cred.Login)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(pass), []byte(cred.Password)) != 1 {
+		return "", false
+	}
+	return user, true
+}
+
+func (s *AppState) authenticateLocalUID(r *http.Request) (string, bool) {
+	conn, ok := r.Context().Value(connContextKey).(net.Conn)
+	if !ok {
+		return "", false
+	}
+	uid, ok := peerUID(conn)
+	if !ok {
+		return "", false
+	}
+	if !isLocalUID(uid) {
+		return "", false
+	}
+	return "local-uid", true
+}
+
+// remoteHost extracts the host part of the repo's remote URL, using a
+// throwaway *git.Repo so callers don't need to carry one around.
+func remoteHost(repoPath string) string {
+	gitRepo, err := git.Open(repoPath)
+	if err != nil {
+		return ""
+	}
+	remote, err := gitRepo.GetRemoteURL()
+	if err != nil || remote == "" {
+		return ""
+	}
+
+	// Normalize scp-like syntax (git@host:org/repo) to a URL so url.Parse
+	// can pull the host out the same way it would for an https remote.
+	if !strings.Contains(remote, "://") {
+		remote = "ssh://" + strings.Replace(remote, ":", "/", 1)
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BatchRequestItem is a single sub-request inside a POST /api/batch body.
+type BatchRequestItem struct {
+	Op     string          `json:"op"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// BatchResponseItem is the result of one BatchRequestItem, mirroring the
+// status/body a direct call to the equivalent endpoint would have produced.
+type BatchResponseItem struct {
+	Status int         `json:"status"`
+	Body   interface{} `json:"body,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+type diffParams struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+type commentsParams struct {
+	FilePath string `json:"file_path,omitempty"`
+}
+
+type notesParams struct {
+	FilePath string `json:"file_path,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+}
+
+// batchHandler executes a batch of read-only sub-requests (diff, status,
+// comments, notes) against a single opened repo and returns their results
+// together, so the UI can refresh its whole view in one round-trip instead
+// of four serialized ones.
+func (s *AppState) batchHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []BatchRequestItem
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results := make([]BatchResponseItem, len(items))
+	for i, item := range items {
+		results[i] = s.execBatchOp(r, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(results)
+}
+
+func (s *AppState) execBatchOp(r *http.Request, item BatchRequestItem) BatchResponseItem {
+	switch item.Op {
+	case "diff":
+		var params diffParams
+		_ = json.Unmarshal(item.Params, &params)
+		resp, err := s.computeDiff(r.Context(), params.Mode)
+		return toBatchResponse(resp, err)
+
+	case "status":
+		resp, err := s.computeStatus(r.Context())
+		return toBatchResponse(resp, err)
+
+	case "comments":
+		var params commentsParams
+		_ = json.Unmarshal(item.Params, &params)
+		resp, err := s.computeComments(r.Context(), params.FilePath)
+		return toBatchResponse(resp, err)
+
+	case "notes":
+		var params notesParams
+		_ = json.Unmarshal(item.Params, &params)
+		resp, err := s.computeNotes(r.Context(), params.FilePath, params.Mode)
+		return toBatchResponse(resp, err)
+
+	default:
+		return BatchResponseItem{Status: http.StatusBadRequest, Error: "unknown op: " + item.Op}
+	}
+}
+
+// toBatchResponse converts a (body, error) pair from a compute helper into
+// a BatchResponseItem, preserving the status carried by a *statusError.
+func toBatchResponse(body interface{}, err error) BatchResponseItem {
+	if err != nil {
+		status := http.StatusInternalServerError
+		if se, ok := err.(*statusError); ok {
+			status = se.status
+		}
+		return BatchResponseItem{Status: status, Error: err.Error()}
+	}
+	return BatchResponseItem{Status: http.StatusOK, Body: body}
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tuist/guck/internal/git"
+	"github.com/tuist/guck/internal/state"
+)
+
+// statusError pairs an error with the HTTP status it should be reported as,
+// so compute helpers shared between single-shot handlers and /api/batch can
+// express "branch not found" (404) vs. a generic failure (500) uniformly.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+
+func newStatusError(status int, err error) *statusError {
+	return &statusError{status: status, err: err}
+}
+
+// resolveMode maps a mode query/batch param ("working", "staged", "branch")
+// onto a git.DiffMode, falling back to def when empty or unrecognized.
+func resolveMode(param string, def git.DiffMode) git.DiffMode {
+	switch param {
+	case "working":
+		return git.DiffModeWorking
+	case "staged":
+		return git.DiffModeStaged
+	case "branch":
+		return git.DiffModeBranch
+	default:
+		return def
+	}
+}
+
+// computeDiff builds the DiffResponse for modeParam (or s.Mode if empty),
+// reusing the cached repo handle. Both diffHandler and the batch endpoint
+// call this so there's one place that knows how to assemble a diff.
+func (s *AppState) computeDiff(ctx context.Context, modeParam string) (*DiffResponse, error) {
+	gitRepo, err := s.repo()
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentBranch, err := gitRepo.CurrentBranchCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentCommit, err := gitRepo.CurrentCommitCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	remoteURL, _ := gitRepo.GetRemoteURL() // Ignore error, remote is optional
+
+	mode := resolveMode(modeParam, s.Mode)
+
+	files, err := gitRepo.GetDiffCtx(ctx, mode, s.BaseBranch)
+	if err != nil {
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "reference not found") || strings.Contains(errMsg, "failed to find branch") {
+			detectedBranch := gitRepo.GetDefaultBranch()
+			return nil, newStatusError(404, fmt.Errorf(
+				"base branch '%s' not found. This repository's default branch appears to be '%s'. "+
+					"Please configure guck with: guck config set base-branch %s",
+				s.BaseBranch, detectedBranch, detectedBranch,
+			))
+		}
+		return nil, newStatusError(500, err)
+	}
+
+	// For working/staged modes, use a synthetic commit identifier
+	stateCommit := currentCommit
+	if mode == git.DiffModeWorking {
+		stateCommit = "working"
+	} else if mode == git.DiffModeStaged {
+		stateCommit = "staged"
+	}
+
+	fileDiffs := []FileDiff{}
+	for _, file := range files {
+		viewed := s.StateManager.IsFileViewed(s.RepoPath, currentBranch, stateCommit, file.Path)
+
+		fileDiffs = append(fileDiffs, FileDiff{
+			Path:      file.Path,
+			Status:    file.Status,
+			Additions: file.Additions,
+			Deletions: file.Deletions,
+			Patch:     file.Patch,
+			Viewed:    viewed,
+		})
+	}
+
+	return &DiffResponse{
+		Files:      fileDiffs,
+		Branch:     currentBranch,
+		Commit:     currentCommit,
+		RepoPath:   s.RepoPath,
+		RemoteURL:  remoteURL,
+		Mode:       string(mode),
+		BaseBranch: s.BaseBranch,
+	}, nil
+}
+
+// computeStatus builds the StatusResponse for the repo's current branch/commit.
+func (s *AppState) computeStatus(ctx context.Context) (*StatusResponse, error) {
+	gitRepo, err := s.repo()
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentBranch, err := gitRepo.CurrentBranchCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentCommit, err := gitRepo.CurrentCommitCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	return &StatusResponse{
+		RepoPath: s.RepoPath,
+		Branch:   currentBranch,
+		Commit:   currentCommit,
+	}, nil
+}
+
+// computeComments returns the comments for the repo's current branch/commit,
+// optionally scoped to filePath.
+func (s *AppState) computeComments(ctx context.Context, filePath string) ([]*state.Comment, error) {
+	gitRepo, err := s.repo()
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentBranch, err := gitRepo.CurrentBranchCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentCommit, err := gitRepo.CurrentCommitCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	var filePathPtr *string
+	if filePath != "" {
+		filePathPtr = &filePath
+	}
+
+	return s.StateManager.GetComments(s.RepoPath, currentBranch, currentCommit, filePathPtr), nil
+}
+
+// computeNotes returns the notes visible for modeParam: for "working"/
+// "staged" it returns every note on the current branch, otherwise just the
+// ones attached to the current commit.
+func (s *AppState) computeNotes(ctx context.Context, filePath string, modeParam string) ([]*state.Note, error) {
+	gitRepo, err := s.repo()
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	currentBranch, err := gitRepo.CurrentBranchCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+
+	var filePathPtr *string
+	if filePath != "" {
+		filePathPtr = &filePath
+	}
+
+	if modeParam == "working" || modeParam == "staged" {
+		return s.StateManager.GetNotesForBranch(s.RepoPath, currentBranch, filePathPtr), nil
+	}
+
+	currentCommit, err := gitRepo.CurrentCommitCtx(ctx)
+	if err != nil {
+		return nil, newStatusError(500, err)
+	}
+	return s.StateManager.GetNotes(s.RepoPath, currentBranch, currentCommit, filePathPtr), nil
+}
+
+// computeETag derives a fingerprint of repo state from branch, commit, the
+// index file's mtime/size, and a cheap worktree status snapshot, so a
+// long-poll caller can tell "nothing changed" apart from "something did"
+// without recomputing the full diff every time.
+func (s *AppState) computeETag(ctx context.Context) (string, error) {
+	gitRepo, err := s.repo()
+	if err != nil {
+		return "", err
+	}
+
+	branch, err := gitRepo.CurrentBranchCtx(ctx)
+	if err != nil {
+		return "", err
+	}
+	commit, err := gitRepo.CurrentCommitCtx(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	indexStat, err := os.Stat(filepath.Join(s.RepoPath, ".git", "index"))
+	indexFingerprint := "no-index"
+	if err == nil {
+		indexFingerprint = fmt.Sprintf("%d-%d", indexStat.Size(), indexStat.ModTime().UnixNano())
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain=v2")
+	cmd.Dir = s.RepoPath
+	worktreeOutput, _ := cmd.Output()
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%s|%s|%s|", branch, commit, indexFingerprint)
+	h.Write(worktreeOutput)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/tuist/guck/internal/git"
+)
+
+// EventType identifies the kind of change pushed over the SSE stream.
+type EventType string
+
+const (
+	EventDiffChanged   EventType = "diff_changed"
+	EventBranchChanged EventType = "branch_changed"
+	EventCommitChanged EventType = "commit_changed"
+	EventCommentAdded  EventType = "comment_added"
+	EventNoteAdded     EventType = "note_added"
+)
+
+// Event is a single message pushed to connected /api/events clients.
+type Event struct {
+	Type EventType   `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// watcherDebounce coalesces bursts of filesystem events (e.g. an editor
+// rewriting several files in one save) into a single emitted event.
+const watcherDebounce = 250 * time.Millisecond
+
+// pollFallbackInterval is how often the watcher re-checks repo state even
+// when fsnotify hasn't fired, as a safety net on filesystems where fsnotify
+// is unreliable (network mounts, some containers).
+const pollFallbackInterval = 2 * time.Second
+
+// Watcher watches a repository for changes to HEAD, the index, and the
+// working tree, and broadcasts typed Events to subscribers.
+type Watcher struct {
+	appState *AppState
+
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+
+	lastBranch string
+	lastCommit string
+}
+
+// NewWatcher creates a Watcher for the given AppState. Call Start to begin
+// watching in the background.
+func NewWatcher(appState *AppState) *Watcher {
+	return &Watcher{
+		appState:    appState,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe registers a new channel that receives broadcast Events; an
+// Event is dropped for the channel while its buffer is full (see Broadcast).
+// Callers must call Unsubscribe when done listening.
+func (w *Watcher) Subscribe() chan Event {
+	ch := make(chan Event, 8)
+	w.mu.Lock()
+	w.subscribers[ch] = struct{}{}
+	w.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe removes and closes a channel previously returned by Subscribe.
+func (w *Watcher) Unsubscribe(ch chan Event) {
+	w.mu.Lock()
+	delete(w.subscribers, ch)
+	w.mu.Unlock()
+	close(ch)
+}
+
+// Broadcast pushes an event to every current subscriber, dropping it for
+// any subscriber whose buffer is full rather than blocking the watcher.
+func (w *Watcher) Broadcast(evt Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subscribers {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// Start begins watching .git/HEAD, .git/index, and the working tree for
+// changes, debouncing bursts and falling back to a low-frequency poll.
+func (w *Watcher) Start() error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create filesystem watcher: %w", err)
+	}
+
+	gitDir := w.appState.RepoPath + "/.git"
+	for _, path := range []string{gitDir + "/HEAD", gitDir + "/index", w.appState.RepoPath} {
+		if err := fsWatcher.Add(path); err != nil {
+			log.Printf("watcher: failed to watch %s: %v", path, err)
+		}
+	}
+
+	go w.run(fsWatcher)
+	return nil
+}
+
+func (w *Watcher) run(fsWatcher *fsnotify.Watcher) {
+	defer fsWatcher.Close()
+
+	var debounce *time.Timer
+	pending := make(chan struct{}, 1)
+	ticker := time.NewTicker(pollFallbackInterval)
+	defer ticker.Stop()
+
+	trigger := func() {
+		if debounce != nil {
+			debounce.Stop()
+		}
+		debounce = time.AfterFunc(watcherDebounce, func() {
+			select {
+			case pending <- struct{}{}:
+			default:
+			}
+		})
+	}
+
+	for {
+		select {
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return
+			}
+			_ = event
+			trigger()
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("watcher: error: %v", err)
+		case <-ticker.C:
+			trigger()
+		case <-pending:
+			w.checkAndBroadcast()
+		}
+	}
+}
+
+// checkAndBroadcast compares the repo's current branch/commit against what
+// was last observed and emits the appropriate events on change.
+func (w *Watcher) checkAndBroadcast() {
+	// HEAD or the index may have changed since the last check; drop the
+	// cached repo handle so the next request reopens it.
+	w.appState.invalidateRepo()
+
+	branch, commit, err := w.appState.currentBranchAndCommit()
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	branchChanged := w.lastBranch != "" && w.lastBranch != branch
+	commitChanged := w.lastCommit != "" && w.lastCommit != commit
+	w.lastBranch = branch
+	w.lastCommit = commit
+	w.mu.Unlock()
+
+	if branchChanged {
+		w.Broadcast(Event{Type: EventBranchChanged, Data: branch})
+	}
+	if commitChanged {
+		w.Broadcast(Event{Type: EventCommitChanged, Data: commit})
+	}
+	// Working tree/index state always warrants a diff refresh, since a
+	// poll tick or fsnotify event can't cheaply tell us whether the diff
+	// actually changed without recomputing it.
+	w.Broadcast(Event{Type: EventDiffChanged})
+}
+
+// eventsHandler serves Server-Sent Events for diff/status/comment/note
+// changes, closing the stream when the client disconnects.
+func (s *AppState) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := s.Watcher.Subscribe()
+	defer s.Watcher.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(evt)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+	}
+}
+
+// currentBranchAndCommit reopens the repo and reads its current branch and
+// commit, for use by the watcher's poll loop.
+func (s *AppState) currentBranchAndCommit() (string, string, error) {
+	gitRepo, err := git.Open(".")
+	if err != nil {
+		return "", "", err
+	}
+	branch, err := gitRepo.CurrentBranch()
+	if err != nil {
+		return "", "", err
+	}
+	commit, err := gitRepo.CurrentCommit()
+	if err != nil {
+		return "", "", err
+	}
+	return branch, commit, nil
+}
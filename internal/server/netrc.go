@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// credential is a resolved username/password pair for a given host.
+type credential struct {
+	Login    string
+	Password string
+}
+
+// lookupNetrc scans ~/.netrc (and ~/_netrc on Windows-style setups) for a
+// machine entry matching host, the same file `curl` and `git` itself
+// consult for HTTP basic auth.
+func lookupNetrc(host string) (credential, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return credential{}, false
+	}
+
+	for _, name := range []string{".netrc", "_netrc"} {
+		if cred, ok := parseNetrcFile(filepath.Join(home, name), host); ok {
+			return cred, true
+		}
+	}
+	return credential{}, false
+}
+
+func parseNetrcFile(path, host string) (credential, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return credential{}, false
+	}
+	defer f.Close()
+
+	fields := strings.Fields(readAll(f))
+
+	var cred credential
+	var machineMatches bool
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if i+1 < len(fields) {
+				machineMatches = fields[i+1] == host
+				cred = credential{}
+				i++
+			}
+		case "login":
+			if machineMatches && i+1 < len(fields) {
+				cred.Login = fields[i+1]
+				i++
+			}
+		case "password":
+			if machineMatches && i+1 < len(fields) {
+				cred.Password = fields[i+1]
+				i++
+				if cred.Login != "" {
+					return cred, true
+				}
+			}
+		}
+	}
+	return credential{}, false
+}
+
+func readAll(f *os.File) string {
+	var sb strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sb.WriteString(scanner.Text())
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+// lookupCookiefile reads the cookie jar configured via `git config
+// http.cookiefile` (Netscape cookie format) looking for an entry whose
+// domain matches host, in case the repo authenticates via cookie rather
+// than a credential helper.
+func lookupCookiefile(repoPath, host string) (credential, bool) {
+	cmd := exec.Command("git", "config", "--get", "http.cookiefile")
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return credential{}, false
+	}
+	path := strings.TrimSpace(string(out))
+	if path == "" {
+		return credential{}, false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return credential{}, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			continue
+		}
+		domain := strings.TrimPrefix(fields[0], ".")
+		if domain != host {
+			continue
+		}
+		// Netscape format: domain, flag, path, secure, expiration, name, value.
+		return credential{Login: fields[5], Password: fields[6]}, true
+	}
+	return credential{}, false
+}
+
+// lookupGitCredential shells out to `git credential fill`, the same helper
+// chain `git` uses for HTTP(S) remotes (credential.helper, .git-credentials,
+// cache, etc.), keyed on host.
+func lookupGitCredential(repoPath, host string) (credential, bool) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Dir = repoPath
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return credential{}, false
+	}
+
+	var cred credential
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "username="):
+			cred.Login = strings.TrimPrefix(line, "username=")
+		case strings.HasPrefix(line, "password="):
+			cred.Password = strings.TrimPrefix(line, "password=")
+		}
+	}
+
+	if cred.Login == "" || cred.Password == "" {
+		return credential{}, false
+	}
+	return cred, true
+}
+
+// resolveCredential looks up basic-auth credentials for host the same way
+// a `git`-aware HTTP client would: first ~/.netrc, then a configured
+// cookiefile, then the full git credential helper chain.
+func resolveCredential(repoPath, host string) (credential, bool) {
+	if cred, ok := lookupNetrc(host); ok {
+		return cred, true
+	}
+	if cred, ok := lookupCookiefile(repoPath, host); ok {
+		return cred, true
+	}
+	return lookupGitCredential(repoPath, host)
+}
@@ -0,0 +1,38 @@
+//go:build linux
+
+package server
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// peerUID reads the uid of the process on the other end of conn via
+// SO_PEERCRED. Only meaningful for Unix domain socket connections.
+func peerUID(conn net.Conn) (int, bool) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, false
+	}
+
+	raw, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0, false
+	}
+
+	var ucred *syscall.Ucred
+	var sockErr error
+	err = raw.Control(func(fd uintptr) {
+		ucred, sockErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil || sockErr != nil || ucred == nil {
+		return 0, false
+	}
+	return int(ucred.Uid), true
+}
+
+// isLocalUID reports whether uid matches the uid this process runs as.
+func isLocalUID(uid int) bool {
+	return uid == os.Getuid()
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package server
+
+import "net"
+
+// peerUID is unsupported on non-Linux platforms: SO_PEERCRED is a Linux
+// socket option. Callers should treat AuthModeLocalUID as unavailable here.
+func peerUID(conn net.Conn) (int, bool) {
+	return 0, false
+}
+
+// isLocalUID always reports false on platforms where peerUID can't resolve
+// a uid in the first place.
+func isLocalUID(uid int) bool {
+	return false
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/tuist/guck/internal/git"
+)
+
+// repoCache holds a single opened *git.Repo per AppState so handlers don't
+// each pay the cost of `git rev-parse --show-toplevel` on every request.
+// The watcher invalidates it on every check it makes (after each debounced
+// filesystem event and each poll tick), whether or not anything changed.
+type repoCache struct {
+	mu   sync.RWMutex
+	repo *git.Repo
+}
+
+// repo returns the cached *git.Repo, opening and caching one if needed.
+func (s *AppState) repo() (*git.Repo, error) {
+	s.repoCache.mu.RLock()
+	if s.repoCache.repo != nil {
+		r := s.repoCache.repo
+		s.repoCache.mu.RUnlock()
+		return r, nil
+	}
+	s.repoCache.mu.RUnlock()
+
+	s.repoCache.mu.Lock()
+	defer s.repoCache.mu.Unlock()
+	if s.repoCache.repo != nil {
+		return s.repoCache.repo, nil
+	}
+
+	r, err := git.Open(".")
+	if err != nil {
+		return nil, err
+	}
+	s.repoCache.repo = r
+	return r, nil
+}
+
+// invalidateRepo drops the cached repo handle so the next call to repo()
+// reopens it. Call this whenever HEAD or the index changes.
+func (s *AppState) invalidateRepo() {
+	s.repoCache.mu.Lock()
+	defer s.repoCache.mu.Unlock()
+	s.repoCache.repo = nil
+}
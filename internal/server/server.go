@@ -2,26 +2,55 @@ package server
 
 import (
 	_ "embed"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
-	"strings"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/tuist/guck/internal/config"
 	"github.com/tuist/guck/internal/git"
 	"github.com/tuist/guck/internal/state"
 )
 
+// defaultRequestTimeout bounds how long a single handler may block on git
+// operations before its context is cancelled.
+const defaultRequestTimeout = 15 * time.Second
+
 //go:embed static/index.html
 var indexHTML string
 
 type AppState struct {
-	RepoPath     string
-	BaseBranch   string
-	Mode         git.DiffMode // "branch", "working", "staged"
-	StateManager *state.Manager
-	mu           sync.Mutex
+	RepoPath       string
+	BaseBranch     string
+	Mode           git.DiffMode // "branch", "working", "staged"
+	StateManager   *state.Manager
+	Watcher        *Watcher
+	RequestTimeout time.Duration
+	AuthMode       AuthMode
+	AuthToken      string
+	repoCache      repoCache
+	mu             sync.Mutex
+}
+
+// timeoutMiddleware bounds every request's context to s.RequestTimeout (or
+// defaultRequestTimeout if unset), so a slow or hung git invocation can't
+// block a handler forever and is cancelled when the client disconnects.
+func (s *AppState) timeoutMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := s.RequestTimeout
+		if timeout <= 0 {
+			timeout = defaultRequestTimeout
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 type DiffResponse struct {
@@ -80,7 +109,12 @@ type StatusResponse struct {
 	Commit   string `json:"commit"`
 }
 
-func Start(port int, baseBranch string, mode string) error {
+// Start launches the server. authMode selects how requests are
+// authenticated ("none", "token", "basic", or "local-uid"); authToken is
+// only consulted when authMode is "token". socketPath is required when
+// authMode is "local-uid" (SO_PEERCRED needs a Unix domain socket, not the
+// default TCP listener) and ignored otherwise.
+func Start(port int, baseBranch string, mode string, authMode string, authToken string, socketPath string) error {
 	gitRepo, err := git.Open(".")
 	if err != nil {
 		return err
@@ -105,35 +139,92 @@ func Start(port int, baseBranch string, mode string) error {
 		diffMode = git.DiffModeStaged
 	}
 
+	if !config.IsValidAuthMode(authMode) {
+		authMode = string(AuthModeNone)
+	}
+	if authMode == string(AuthModeLocalUID) && socketPath == "" {
+		return fmt.Errorf("auth_mode %q requires socket_path to be set: SO_PEERCRED only works over a Unix domain socket", AuthModeLocalUID)
+	}
+
 	appState := &AppState{
-		RepoPath:     repoPath,
-		BaseBranch:   baseBranch,
-		Mode:         diffMode,
-		StateManager: stateMgr,
+		RepoPath:       repoPath,
+		BaseBranch:     baseBranch,
+		Mode:           diffMode,
+		StateManager:   stateMgr,
+		RequestTimeout: defaultRequestTimeout,
+		AuthMode:       AuthMode(authMode),
+		AuthToken:      authToken,
+	}
+	appState.Watcher = NewWatcher(appState)
+	if err := appState.Watcher.Start(); err != nil {
+		log.Printf("failed to start file watcher, live updates disabled: %v", err)
 	}
 
 	r := mux.NewRouter()
+	r.Use(appState.authMiddleware)
 	r.HandleFunc("/", appState.indexHandler).Methods("GET")
+	// /api/events is long-lived (SSE) and /api/diff can long-poll for up to
+	// its own ?timeout= duration, so both are exempt from the per-request
+	// timeout applied to every other route below. /api/events ends when the
+	// client disconnects; diffHandler sets its own deadline.
+	r.HandleFunc("/api/events", appState.eventsHandler).Methods("GET")
 	r.HandleFunc("/api/diff", appState.diffHandler).Methods("GET")
-	r.HandleFunc("/api/mark-viewed", appState.markViewedHandler).Methods("POST")
-	r.HandleFunc("/api/unmark-viewed", appState.unmarkViewedHandler).Methods("POST")
-	r.HandleFunc("/api/status", appState.statusHandler).Methods("GET")
-	r.HandleFunc("/api/comments", appState.getCommentsHandler).Methods("GET")
-	r.HandleFunc("/api/comments", appState.addCommentHandler).Methods("POST")
-	r.HandleFunc("/api/comments/resolve", appState.resolveCommentHandler).Methods("POST")
-	r.HandleFunc("/api/notes", appState.getNotesHandler).Methods("GET")
-	r.HandleFunc("/api/notes", appState.addNoteHandler).Methods("POST")
-	r.HandleFunc("/api/notes/dismiss", appState.dismissNoteHandler).Methods("POST")
+	r.Handle("/api/mark-viewed", appState.timeoutMiddleware(http.HandlerFunc(appState.markViewedHandler))).Methods("POST")
+	r.Handle("/api/unmark-viewed", appState.timeoutMiddleware(http.HandlerFunc(appState.unmarkViewedHandler))).Methods("POST")
+	r.Handle("/api/status", appState.timeoutMiddleware(http.HandlerFunc(appState.statusHandler))).Methods("GET")
+	r.Handle("/api/comments", appState.timeoutMiddleware(http.HandlerFunc(appState.getCommentsHandler))).Methods("GET")
+	r.Handle("/api/comments", appState.timeoutMiddleware(http.HandlerFunc(appState.addCommentHandler))).Methods("POST")
+	r.Handle("/api/comments/resolve", appState.timeoutMiddleware(http.HandlerFunc(appState.resolveCommentHandler))).Methods("POST")
+	r.Handle("/api/notes", appState.timeoutMiddleware(http.HandlerFunc(appState.getNotesHandler))).Methods("GET")
+	r.Handle("/api/notes", appState.timeoutMiddleware(http.HandlerFunc(appState.addNoteHandler))).Methods("POST")
+	r.Handle("/api/notes/dismiss", appState.timeoutMiddleware(http.HandlerFunc(appState.dismissNoteHandler))).Methods("POST")
+	r.Handle("/api/batch", appState.timeoutMiddleware(http.HandlerFunc(appState.batchHandler))).Methods("POST")
 
-	addr := fmt.Sprintf("127.0.0.1:%d", port)
-	fmt.Printf("Starting server on http://%s\n", addr)
 	fmt.Printf("Mode: %s", mode)
 	if mode == "branch" {
 		fmt.Printf(" (comparing against %s)", baseBranch)
 	}
 	fmt.Println()
 
-	return http.ListenAndServe(addr, r)
+	httpServer := &http.Server{
+		Handler: r,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return saveConnContext(ctx, c)
+		},
+	}
+
+	if authMode == string(AuthModeLocalUID) {
+		listener, err := listenLocalUIDSocket(socketPath)
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		fmt.Printf("Starting server on unix:%s\n", socketPath)
+		return httpServer.Serve(listener)
+	}
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	fmt.Printf("Starting server on http://%s\n", addr)
+	return httpServer.ListenAndServe()
+}
+
+// listenLocalUIDSocket binds a Unix domain socket at socketPath for the
+// local-uid auth mode, replacing any stale socket file left behind by a
+// previous run and restricting it to the owning user.
+func listenLocalUIDSocket(socketPath string) (net.Listener, error) {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket at %s: %w", socketPath, err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %w", socketPath, err)
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict permissions on socket %s: %w", socketPath, err)
+	}
+	return listener, nil
 }
 
 func (s *AppState) indexHandler(w http.ResponseWriter, r *http.Request) {
@@ -142,93 +233,104 @@ func (s *AppState) indexHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *AppState) diffHandler(w http.ResponseWriter, r *http.Request) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	gitRepo, err := git.Open(".")
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	waitEtag := r.URL.Query().Get("wait")
+	if waitEtag != "" {
+		s.longPollDiff(w, r, waitEtag)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	// The route is not wrapped in timeoutMiddleware (the long-poll form sets
+	// its own deadline), so bound a plain request here.
+	timeout := s.RequestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
 	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	response, err := s.computeDiff(ctx, r.URL.Query().Get("mode"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStatusError(w, err)
 		return
 	}
 
-	remoteURL, _ := gitRepo.GetRemoteURL() // Ignore error, remote is optional
-
-	// Check if mode is overridden via query param
-	mode := s.Mode
-	if modeParam := r.URL.Query().Get("mode"); modeParam != "" {
-		switch modeParam {
-		case "working":
-			mode = git.DiffModeWorking
-		case "staged":
-			mode = git.DiffModeStaged
-		case "branch":
-			mode = git.DiffModeBranch
-		}
+	if etag, err := s.computeETag(ctx); err == nil {
+		w.Header().Set("ETag", etag)
 	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response) // Ignore encode error for HTTP response
+}
 
-	files, err := gitRepo.GetDiff(mode, s.BaseBranch)
-	if err != nil {
-		// Check if it's a "branch not found" error (only relevant for branch mode)
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "reference not found") || strings.Contains(errMsg, "failed to find branch") {
-			detectedBranch := gitRepo.GetDefaultBranch()
-			http.Error(w, fmt.Sprintf(
-				"Base branch '%s' not found. This repository's default branch appears to be '%s'. "+
-					"Please configure guck with: guck config set base-branch %s",
-				s.BaseBranch, detectedBranch, detectedBranch,
-			), http.StatusNotFound)
-			return
+// longPollDiffInterval is how often a long-poll /api/diff request re-checks
+// the ETag while waiting for it to change.
+const longPollDiffInterval = 300 * time.Millisecond
+
+// longPollDiffDefaultTimeout is used when the caller doesn't supply ?timeout.
+const longPollDiffDefaultTimeout = 30 * time.Second
+
+// longPollDiff blocks until the repo's ETag differs from waitEtag or the
+// requested timeout elapses, returning 304 on timeout so the caller can
+// immediately re-poll without a round-trip of dead time.
+func (s *AppState) longPollDiff(w http.ResponseWriter, r *http.Request, waitEtag string) {
+	timeout := longPollDiffDefaultTimeout
+	if raw := r.URL.Query().Get("timeout"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil {
+			timeout = parsed
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
 	}
 
-	// For working/staged modes, use a synthetic commit identifier
-	stateCommit := currentCommit
-	if mode == git.DiffModeWorking {
-		stateCommit = "working"
-	} else if mode == git.DiffModeStaged {
-		stateCommit = "staged"
-	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
 
-	fileDiffs := []FileDiff{}
-	for _, file := range files {
-		viewed := s.StateManager.IsFileViewed(s.RepoPath, currentBranch, stateCommit, file.Path)
+	ticker := time.NewTicker(longPollDiffInterval)
+	defer ticker.Stop()
 
-		fileDiffs = append(fileDiffs, FileDiff{
-			Path:      file.Path,
-			Status:    file.Status,
-			Additions: file.Additions,
-			Deletions: file.Deletions,
-			Patch:     file.Patch,
-			Viewed:    viewed,
-		})
-	}
+	for {
+		s.mu.Lock()
+		etag, err := s.computeETag(ctx)
+		if err != nil {
+			s.mu.Unlock()
+			if ctx.Err() != nil {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if etag != waitEtag {
+			response, err := s.computeDiff(ctx, r.URL.Query().Get("mode"))
+			s.mu.Unlock()
+			if err != nil {
+				writeStatusError(w, err)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+			return
+		}
+		s.mu.Unlock()
 
-	response := DiffResponse{
-		Files:      fileDiffs,
-		Branch:     currentBranch,
-		Commit:     currentCommit,
-		RepoPath:   s.RepoPath,
-		RemoteURL:  remoteURL,
-		Mode:       string(mode),
-		BaseBranch: s.BaseBranch,
+		select {
+		case <-ctx.Done():
+			w.WriteHeader(http.StatusNotModified)
+			return
+		case <-ticker.C:
+		}
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(response) // Ignore encode error for HTTP response
+// writeStatusError reports err with the HTTP status carried on it by a
+// *statusError, falling back to 500 for a plain error.
+func writeStatusError(w http.ResponseWriter, err error) {
+	if se, ok := err.(*statusError); ok {
+		http.Error(w, se.Error(), se.status)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
 func (s *AppState) markViewedHandler(w http.ResponseWriter, r *http.Request) {
@@ -241,19 +343,19 @@ func (s *AppState) markViewedHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gitRepo, err := git.Open(".")
+	gitRepo, err := s.repo()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
+	currentBranch, err := gitRepo.CurrentBranchCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	currentCommit, err := gitRepo.CurrentCommitCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -277,19 +379,19 @@ func (s *AppState) unmarkViewedHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gitRepo, err := git.Open(".")
+	gitRepo, err := s.repo()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
+	currentBranch, err := gitRepo.CurrentBranchCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	currentCommit, err := gitRepo.CurrentCommitCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -304,30 +406,12 @@ func (s *AppState) unmarkViewedHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *AppState) statusHandler(w http.ResponseWriter, r *http.Request) {
-	gitRepo, err := git.Open(".")
+	response, err := s.computeStatus(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStatusError(w, err)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	currentCommit, err := gitRepo.CurrentCommit()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	response := StatusResponse{
-		RepoPath: s.RepoPath,
-		Branch:   currentBranch,
-		Commit:   currentCommit,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(response) // Ignore encode error for HTTP response
 }
@@ -336,32 +420,12 @@ func (s *AppState) getCommentsHandler(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	gitRepo, err := git.Open(".")
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	currentBranch, err := gitRepo.CurrentBranch()
+	comments, err := s.computeComments(r.Context(), r.URL.Query().Get("file_path"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStatusError(w, err)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	filePath := r.URL.Query().Get("file_path")
-	var filePathPtr *string
-	if filePath != "" {
-		filePathPtr = &filePath
-	}
-
-	comments := s.StateManager.GetComments(s.RepoPath, currentBranch, currentCommit, filePathPtr)
-
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(comments) // Ignore encode error for HTTP response
 }
@@ -376,19 +440,19 @@ func (s *AppState) addCommentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gitRepo, err := git.Open(".")
+	gitRepo, err := s.repo()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
+	currentBranch, err := gitRepo.CurrentBranchCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	currentCommit, err := gitRepo.CurrentCommitCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -399,6 +463,9 @@ func (s *AppState) addCommentHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if s.Watcher != nil {
+		s.Watcher.Broadcast(Event{Type: EventCommentAdded, Data: comment})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(comment) // Ignore encode error for HTTP response
@@ -414,25 +481,26 @@ func (s *AppState) resolveCommentHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	gitRepo, err := git.Open(".")
+	gitRepo, err := s.repo()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
+	currentBranch, err := gitRepo.CurrentBranchCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	currentCommit, err := gitRepo.CurrentCommitCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if err := s.StateManager.ResolveComment(s.RepoPath, currentBranch, currentCommit, payload.CommentID, "web-ui"); err != nil {
+	principal, _ := PrincipalFromContext(r.Context())
+	if err := s.StateManager.ResolveComment(s.RepoPath, currentBranch, currentCommit, payload.CommentID, principal); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -444,42 +512,12 @@ func (s *AppState) getNotesHandler(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	gitRepo, err := git.Open(".")
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	currentBranch, err := gitRepo.CurrentBranch()
+	notes, err := s.computeNotes(r.Context(), r.URL.Query().Get("file_path"), r.URL.Query().Get("mode"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStatusError(w, err)
 		return
 	}
 
-	filePath := r.URL.Query().Get("file_path")
-	var filePathPtr *string
-	if filePath != "" {
-		filePathPtr = &filePath
-	}
-
-	// Check mode - for working/staged modes, get all notes for the branch
-	// This ensures notes are visible regardless of which mode you're viewing
-	mode := r.URL.Query().Get("mode")
-	var notes []*state.Note
-
-	if mode == "working" || mode == "staged" {
-		// Get notes across all commits for this branch
-		notes = s.StateManager.GetNotesForBranch(s.RepoPath, currentBranch, filePathPtr)
-	} else {
-		// Branch mode - get notes for specific commit
-		currentCommit, err := gitRepo.CurrentCommit()
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		notes = s.StateManager.GetNotes(s.RepoPath, currentBranch, currentCommit, filePathPtr)
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(notes) // Ignore encode error for HTTP response
 }
@@ -494,19 +532,19 @@ func (s *AppState) addNoteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gitRepo, err := git.Open(".")
+	gitRepo, err := s.repo()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
+	currentBranch, err := gitRepo.CurrentBranchCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	currentCommit, err := gitRepo.CurrentCommitCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -533,6 +571,9 @@ func (s *AppState) addNoteHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if s.Watcher != nil {
+		s.Watcher.Broadcast(Event{Type: EventNoteAdded, Data: note})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(note) // Ignore encode error for HTTP response
@@ -548,25 +589,26 @@ func (s *AppState) dismissNoteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gitRepo, err := git.Open(".")
+	gitRepo, err := s.repo()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentBranch, err := gitRepo.CurrentBranch()
+	currentBranch, err := gitRepo.CurrentBranchCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	currentCommit, err := gitRepo.CurrentCommit()
+	currentCommit, err := gitRepo.CurrentCommitCtx(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if err := s.StateManager.DismissNote(s.RepoPath, currentBranch, currentCommit, payload.NoteID, "web-ui"); err != nil {
+	principal, _ := PrincipalFromContext(r.Context())
+	if err := s.StateManager.DismissNote(s.RepoPath, currentBranch, currentCommit, payload.NoteID, principal); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}